@@ -0,0 +1,183 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.55
+
+import (
+	"context"
+	"time"
+
+	null "gopkg.in/guregu/null.v4"
+
+	"github.com/thanishsid/dingilink-server/api/graphql/generated"
+	"github.com/thanishsid/dingilink-server/internal/model"
+	"github.com/thanishsid/dingilink-server/internal/services"
+)
+
+// Owner is the resolver for the owner field.
+func (r *channelResolver) Owner(ctx context.Context, obj *model.Channel) (*model.User, error) {
+	return r.Dataloader.GetUser(ctx, obj.CreatedBy)
+}
+
+// MemberCount is the resolver for the memberCount field.
+func (r *channelResolver) MemberCount(ctx context.Context, obj *model.Channel) (int, error) {
+	count, err := r.Dataloader.GetChannelMemberCount(ctx, obj.ID)
+	return int(count), err
+}
+
+// MyRole is the resolver for the myRole field.
+func (r *channelResolver) MyRole(ctx context.Context, obj *model.Channel) (*model.ChannelRole, error) {
+	return r.ChannelService.GetMyChannelRole(ctx, obj.ID)
+}
+
+// InviteCode is the resolver for the inviteCode field.
+func (r *channelResolver) InviteCode(ctx context.Context, obj *model.Channel) (*string, error) {
+	return r.ChannelService.GetChannelInviteCode(ctx, obj)
+}
+
+// Posts is the resolver for the posts field.
+func (r *channelResolver) Posts(ctx context.Context, obj *model.Channel, first *int, after *string) (*model.ChannelPostConnection, error) {
+	input := services.GetChannelPostsInput{
+		After: after,
+	}
+
+	if first != nil {
+		input.First = null.IntFrom(int64(*first)).Ptr()
+	}
+
+	return r.ChannelService.GetChannelPosts(ctx, obj, input)
+}
+
+// Author is the resolver for the author field.
+func (r *channelPostResolver) Author(ctx context.Context, obj *model.ChannelPost) (*model.User, error) {
+	return r.Dataloader.GetUser(ctx, obj.AuthorID)
+}
+
+// PostedAt is the resolver for the postedAt field.
+func (r *channelPostResolver) PostedAt(ctx context.Context, obj *model.ChannelPost) (*time.Time, error) {
+	return null.NewTime(obj.PostedAt.Time, obj.PostedAt.Valid).Ptr(), nil
+}
+
+// Reactions is the resolver for the reactions field.
+func (r *channelPostResolver) Reactions(ctx context.Context, obj *model.ChannelPost) ([]*model.ChannelPostReactionCount, error) {
+	return r.Dataloader.GetChannelPostReactions(ctx, obj.ID)
+}
+
+// CreateChannel is the resolver for the createChannel field.
+func (r *mutationsResolver) CreateChannel(ctx context.Context, input services.CreateChannelInput) (*model.Channel, error) {
+	return r.ChannelService.CreateChannel(ctx, input)
+}
+
+// UpdateChannel is the resolver for the updateChannel field.
+func (r *mutationsResolver) UpdateChannel(ctx context.Context, input services.UpdateChannelInput) (*model.Channel, error) {
+	return r.ChannelService.UpdateChannel(ctx, input)
+}
+
+// DeleteChannel is the resolver for the deleteChannel field.
+func (r *mutationsResolver) DeleteChannel(ctx context.Context, channelID string) (bool, error) {
+	if err := r.ChannelService.DeleteChannel(ctx, channelID); err != nil {
+		return fail(err)
+	}
+
+	return success()
+}
+
+// JoinChannel is the resolver for the joinChannel field.
+func (r *mutationsResolver) JoinChannel(ctx context.Context, channelID string) (*model.Channel, error) {
+	return r.ChannelService.JoinChannel(ctx, channelID)
+}
+
+// JoinChannelByInvite is the resolver for the joinChannelByInvite field.
+func (r *mutationsResolver) JoinChannelByInvite(ctx context.Context, code string) (*model.Channel, error) {
+	return r.ChannelService.JoinChannelByInvite(ctx, code)
+}
+
+// LeaveChannel is the resolver for the leaveChannel field.
+func (r *mutationsResolver) LeaveChannel(ctx context.Context, channelID string) (bool, error) {
+	if err := r.ChannelService.LeaveChannel(ctx, channelID); err != nil {
+		return fail(err)
+	}
+
+	return success()
+}
+
+// ResetChannelInvite is the resolver for the resetChannelInvite field.
+func (r *mutationsResolver) ResetChannelInvite(ctx context.Context, channelID string) (*model.Channel, error) {
+	return r.ChannelService.ResetChannelInvite(ctx, channelID)
+}
+
+// SetChannelAdmin is the resolver for the setChannelAdmin field.
+func (r *mutationsResolver) SetChannelAdmin(ctx context.Context, input services.SetChannelAdminInput) (bool, error) {
+	if err := r.ChannelService.SetChannelAdmin(ctx, input); err != nil {
+		return fail(err)
+	}
+
+	return success()
+}
+
+// CreateChannelPost is the resolver for the createChannelPost field.
+func (r *mutationsResolver) CreateChannelPost(ctx context.Context, input services.CreateChannelPostInput) (*model.ChannelPost, error) {
+	return r.ChannelService.CreateChannelPost(ctx, input)
+}
+
+// DeleteChannelPost is the resolver for the deleteChannelPost field.
+func (r *mutationsResolver) DeleteChannelPost(ctx context.Context, postID string) (bool, error) {
+	if err := r.ChannelService.DeleteChannelPost(ctx, postID); err != nil {
+		return fail(err)
+	}
+
+	return success()
+}
+
+// ReactToChannelPost is the resolver for the reactToChannelPost field.
+func (r *mutationsResolver) ReactToChannelPost(ctx context.Context, postID string, emoji string) (bool, error) {
+	if err := r.ChannelService.ReactToChannelPost(ctx, postID, emoji); err != nil {
+		return fail(err)
+	}
+
+	return success()
+}
+
+// RemoveChannelPostReaction is the resolver for the removeChannelPostReaction field.
+func (r *mutationsResolver) RemoveChannelPostReaction(ctx context.Context, postID string) (bool, error) {
+	if err := r.ChannelService.RemoveChannelPostReaction(ctx, postID); err != nil {
+		return fail(err)
+	}
+
+	return success()
+}
+
+// Channel is the resolver for the channel field.
+func (r *queriesResolver) Channel(ctx context.Context, id string) (*model.Channel, error) {
+	return r.ChannelService.GetChannel(ctx, id)
+}
+
+// ChannelByHandle is the resolver for the channelByHandle field.
+func (r *queriesResolver) ChannelByHandle(ctx context.Context, handle string) (*model.Channel, error) {
+	return r.ChannelService.GetChannelByHandle(ctx, handle)
+}
+
+// SearchChannels is the resolver for the searchChannels field.
+func (r *queriesResolver) SearchChannels(ctx context.Context, search string) ([]*model.Channel, error) {
+	return r.ChannelService.SearchChannels(ctx, search)
+}
+
+// MyChannels is the resolver for the myChannels field.
+func (r *queriesResolver) MyChannels(ctx context.Context) ([]*model.Channel, error) {
+	return r.ChannelService.GetMyChannels(ctx)
+}
+
+// ChannelEvents is the resolver for the channelEvents field.
+func (r *subscriptionsResolver) ChannelEvents(ctx context.Context, channelID string) (<-chan *model.ChannelEvent, error) {
+	return r.ChannelService.SubscribeToChannelEvents(ctx, channelID)
+}
+
+// Channel returns generated.ChannelResolver implementation.
+func (r *Resolver) Channel() generated.ChannelResolver { return &channelResolver{r} }
+
+// ChannelPost returns generated.ChannelPostResolver implementation.
+func (r *Resolver) ChannelPost() generated.ChannelPostResolver { return &channelPostResolver{r} }
+
+type channelResolver struct{ *Resolver }
+type channelPostResolver struct{ *Resolver }
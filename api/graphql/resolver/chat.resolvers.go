@@ -6,11 +6,43 @@ package resolver
 
 import (
 	"context"
+	"time"
+
+	null "gopkg.in/guregu/null.v4"
 
 	"github.com/thanishsid/dingilink-server/api/graphql/generated"
 	"github.com/thanishsid/dingilink-server/internal/model"
+	"github.com/thanishsid/dingilink-server/internal/services"
 )
 
+// ReplyForMessage is the resolver for the replyForMessage field.
+func (r *chatDraftResolver) ReplyForMessage(ctx context.Context, obj *model.ChatDraft) (model.Message, error) {
+	if obj.ReplyForMessageID == nil {
+		return nil, nil
+	}
+
+	return r.MessageService.GetMessage(ctx, *obj.ReplyForMessageID)
+}
+
+// UpdatedAt is the resolver for the updatedAt field.
+func (r *chatDraftResolver) UpdatedAt(ctx context.Context, obj *model.ChatDraft) (*time.Time, error) {
+	return null.NewTime(obj.UpdatedAt.Time, obj.UpdatedAt.Valid).Ptr(), nil
+}
+
+// Chat is the resolver for the chat field.
+func (r *chatListEventResolver) Chat(ctx context.Context, obj *model.ChatListEvent) (model.ChatPreview, error) {
+	if obj.Preview == nil {
+		return nil, nil
+	}
+
+	return obj.Preview.Build(), nil
+}
+
+// MutedUntil is the resolver for the mutedUntil field.
+func (r *chatSettingsResolver) MutedUntil(ctx context.Context, obj *model.ChatSettings) (*time.Time, error) {
+	return null.NewTime(obj.MutedUntil.Time, obj.MutedUntil.Valid).Ptr(), nil
+}
+
 // User is the resolver for the user field.
 func (r *directChatResolver) User(ctx context.Context, obj *model.DirectChat) (*model.User, error) {
 	return r.Dataloader.GetUser(ctx, obj.UserID)
@@ -26,6 +58,11 @@ func (r *directChatPreviewResolver) LastMessage(ctx context.Context, obj *model.
 	return r.Dataloader.GetMessage(ctx, obj.LastMessageID)
 }
 
+// MutedUntil is the resolver for the mutedUntil field.
+func (r *directChatPreviewResolver) MutedUntil(ctx context.Context, obj *model.DirectChatPreview) (*time.Time, error) {
+	return null.NewTime(obj.MutedUntil.Time, obj.MutedUntil.Valid).Ptr(), nil
+}
+
 // Group is the resolver for the group field.
 func (r *groupChatResolver) Group(ctx context.Context, obj *model.GroupChat) (*model.Group, error) {
 	return r.Dataloader.GetGroup(ctx, obj.GroupID)
@@ -38,12 +75,78 @@ func (r *groupChatPreviewResolver) Group(ctx context.Context, obj *model.GroupCh
 
 // LastMessage is the resolver for the lastMessage field.
 func (r *groupChatPreviewResolver) LastMessage(ctx context.Context, obj *model.GroupChatPreview) (model.Message, error) {
+	// Previews can outlive the membership in events sent before the user left, only current members see the last message.
+	isMember, err := r.isGroupMember(ctx, obj.GroupID)
+	if err != nil || !isMember {
+		return nil, err
+	}
+
 	return r.Dataloader.GetMessage(ctx, obj.LastMessageID)
 }
 
+// MutedUntil is the resolver for the mutedUntil field.
+func (r *groupChatPreviewResolver) MutedUntil(ctx context.Context, obj *model.GroupChatPreview) (*time.Time, error) {
+	return null.NewTime(obj.MutedUntil.Time, obj.MutedUntil.Valid).Ptr(), nil
+}
+
+// SaveDraft is the resolver for the saveDraft field.
+func (r *mutationsResolver) SaveDraft(ctx context.Context, input services.SaveDraftInput) (*model.ChatDraft, error) {
+	return r.MessageService.SaveDraft(ctx, input)
+}
+
+// MarkChatAsRead is the resolver for the markChatAsRead field.
+func (r *mutationsResolver) MarkChatAsRead(ctx context.Context, chatID string) (bool, error) {
+	if err := r.MessageService.MarkChatAsRead(ctx, chatID); err != nil {
+		return fail(err)
+	}
+
+	return success()
+}
+
+// ArchiveChat is the resolver for the archiveChat field.
+func (r *mutationsResolver) ArchiveChat(ctx context.Context, chatID string) (*model.ChatSettings, error) {
+	return r.MessageService.SetChatArchived(ctx, chatID, true)
+}
+
+// UnarchiveChat is the resolver for the unarchiveChat field.
+func (r *mutationsResolver) UnarchiveChat(ctx context.Context, chatID string) (*model.ChatSettings, error) {
+	return r.MessageService.SetChatArchived(ctx, chatID, false)
+}
+
+// PinChat is the resolver for the pinChat field.
+func (r *mutationsResolver) PinChat(ctx context.Context, chatID string) (*model.ChatSettings, error) {
+	return r.MessageService.PinChat(ctx, chatID)
+}
+
+// UnpinChat is the resolver for the unpinChat field.
+func (r *mutationsResolver) UnpinChat(ctx context.Context, chatID string) (*model.ChatSettings, error) {
+	return r.MessageService.UnpinChat(ctx, chatID)
+}
+
+// MuteChat is the resolver for the muteChat field.
+func (r *mutationsResolver) MuteChat(ctx context.Context, input services.MuteChatInput) (*model.ChatSettings, error) {
+	return r.MessageService.MuteChat(ctx, input)
+}
+
+// UnmuteChat is the resolver for the unmuteChat field.
+func (r *mutationsResolver) UnmuteChat(ctx context.Context, chatID string) (*model.ChatSettings, error) {
+	return r.MessageService.UnmuteChat(ctx, chatID)
+}
+
+// ExportChat is the resolver for the exportChat field.
+func (r *mutationsResolver) ExportChat(ctx context.Context, input services.ExportChatInput) (string, error) {
+	return r.ExportService.ExportChat(ctx, input)
+}
+
 // Chats is the resolver for the chats field.
-func (r *queriesResolver) Chats(ctx context.Context) ([]model.ChatPreview, error) {
-	return r.MessageService.GetChats(ctx)
+func (r *queriesResolver) Chats(ctx context.Context, input *services.GetChatsInput) (*model.ChatPreviewConnection, error) {
+	var i services.GetChatsInput
+
+	if input != nil {
+		i = *input
+	}
+
+	return r.MessageService.GetChats(ctx, i)
 }
 
 // Chat is the resolver for the chat field.
@@ -51,6 +154,25 @@ func (r *queriesResolver) Chat(ctx context.Context, chatID string) (model.Chat,
 	return r.MessageService.GetChat(ctx, chatID)
 }
 
+// DraftEvents is the resolver for the draftEvents field.
+func (r *subscriptionsResolver) DraftEvents(ctx context.Context) (<-chan *model.DraftEvent, error) {
+	return r.MessageService.SubscribeToDraftEvents(ctx)
+}
+
+// ChatListEvents is the resolver for the chatListEvents field.
+func (r *subscriptionsResolver) ChatListEvents(ctx context.Context) (<-chan *model.ChatListEvent, error) {
+	return r.MessageService.SubscribeToChatListEvents(ctx)
+}
+
+// ChatDraft returns generated.ChatDraftResolver implementation.
+func (r *Resolver) ChatDraft() generated.ChatDraftResolver { return &chatDraftResolver{r} }
+
+// ChatListEvent returns generated.ChatListEventResolver implementation.
+func (r *Resolver) ChatListEvent() generated.ChatListEventResolver { return &chatListEventResolver{r} }
+
+// ChatSettings returns generated.ChatSettingsResolver implementation.
+func (r *Resolver) ChatSettings() generated.ChatSettingsResolver { return &chatSettingsResolver{r} }
+
 // DirectChat returns generated.DirectChatResolver implementation.
 func (r *Resolver) DirectChat() generated.DirectChatResolver { return &directChatResolver{r} }
 
@@ -67,6 +189,9 @@ func (r *Resolver) GroupChatPreview() generated.GroupChatPreviewResolver {
 	return &groupChatPreviewResolver{r}
 }
 
+type chatDraftResolver struct{ *Resolver }
+type chatListEventResolver struct{ *Resolver }
+type chatSettingsResolver struct{ *Resolver }
 type directChatResolver struct{ *Resolver }
 type directChatPreviewResolver struct{ *Resolver }
 type groupChatResolver struct{ *Resolver }
@@ -7,6 +7,9 @@ package resolver
 import (
 	"context"
 
+	null "gopkg.in/guregu/null.v4"
+
+	"github.com/thanishsid/dingilink-server/api/graphql/generated"
 	"github.com/thanishsid/dingilink-server/internal/model"
 	"github.com/thanishsid/dingilink-server/internal/services"
 )
@@ -67,7 +70,60 @@ func (r *mutationsResolver) UpdateCurrentUser(ctx context.Context, input service
 	return r.UserService.UpdateCurrentUser(ctx, input)
 }
 
+// SetLocationVisibility is the resolver for the setLocationVisibility field.
+func (r *mutationsResolver) SetLocationVisibility(ctx context.Context, visible bool) (bool, error) {
+	if err := r.UserService.SetLocationVisibility(ctx, visible); err != nil {
+		return fail(err)
+	}
+
+	return success()
+}
+
 // CurrentUser is the resolver for the currentUser field.
 func (r *queriesResolver) CurrentUser(ctx context.Context) (*model.User, error) {
 	return r.UserService.GetCurrentUser(ctx)
 }
+
+// SearchUsers is the resolver for the searchUsers field.
+func (r *queriesResolver) SearchUsers(ctx context.Context, query string, first *int, after *string) (*model.UserConnection, error) {
+	input := services.SearchUsersInput{
+		Query: query,
+		After: after,
+	}
+
+	if first != nil {
+		input.First = null.IntFrom(int64(*first)).Ptr()
+	}
+
+	return r.UserService.SearchUsers(ctx, input)
+}
+
+// NearbyUsers is the resolver for the nearbyUsers field.
+func (r *queriesResolver) NearbyUsers(ctx context.Context, radiusKm float64) ([]*model.NearbyUser, error) {
+	return r.UserService.GetNearbyUsers(ctx, radiusKm)
+}
+
+// Image is the resolver for the image field.
+func (r *userResolver) Image(ctx context.Context, obj *model.User) (*string, error) {
+	blocked, err := r.isBlockedWith(ctx, obj.ID)
+	if err != nil || blocked {
+		return nil, err
+	}
+
+	return obj.Image, nil
+}
+
+// Online is the resolver for the online field.
+func (r *userResolver) Online(ctx context.Context, obj *model.User) (bool, error) {
+	blocked, err := r.isBlockedWith(ctx, obj.ID)
+	if err != nil || blocked {
+		return false, err
+	}
+
+	return obj.Online, nil
+}
+
+// User returns generated.UserResolver implementation.
+func (r *Resolver) User() generated.UserResolver { return &userResolver{r} }
+
+type userResolver struct{ *Resolver }
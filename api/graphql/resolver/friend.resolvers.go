@@ -0,0 +1,176 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.55
+
+import (
+	"context"
+	"time"
+
+	null "gopkg.in/guregu/null.v4"
+
+	"github.com/thanishsid/dingilink-server/api/graphql/generated"
+	"github.com/thanishsid/dingilink-server/internal/model"
+	"github.com/thanishsid/dingilink-server/internal/services"
+)
+
+// User is the resolver for the user field.
+func (r *blockedUserResolver) User(ctx context.Context, obj *model.BlockedUser) (*model.User, error) {
+	return r.Dataloader.GetUser(ctx, obj.UserID)
+}
+
+// BlockedAt is the resolver for the blockedAt field.
+func (r *blockedUserResolver) BlockedAt(ctx context.Context, obj *model.BlockedUser) (*time.Time, error) {
+	return null.NewTime(obj.BlockedAt.Time, obj.BlockedAt.Valid).Ptr(), nil
+}
+
+// User is the resolver for the user field.
+func (r *friendResolver) User(ctx context.Context, obj *model.Friend) (*model.User, error) {
+	return r.Dataloader.GetUser(ctx, obj.UserID)
+}
+
+// Since is the resolver for the since field.
+func (r *friendResolver) Since(ctx context.Context, obj *model.Friend) (*time.Time, error) {
+	return null.NewTime(obj.Since.Time, obj.Since.Valid).Ptr(), nil
+}
+
+// Sender is the resolver for the sender field.
+func (r *friendRequestResolver) Sender(ctx context.Context, obj *model.FriendRequest) (*model.User, error) {
+	return r.Dataloader.GetUser(ctx, obj.SenderID)
+}
+
+// Recipient is the resolver for the recipient field.
+func (r *friendRequestResolver) Recipient(ctx context.Context, obj *model.FriendRequest) (*model.User, error) {
+	return r.Dataloader.GetUser(ctx, obj.RecipientID)
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *friendRequestResolver) CreatedAt(ctx context.Context, obj *model.FriendRequest) (*time.Time, error) {
+	return null.NewTime(obj.CreatedAt.Time, obj.CreatedAt.Valid).Ptr(), nil
+}
+
+// SendFriendRequest is the resolver for the sendFriendRequest field.
+func (r *mutationsResolver) SendFriendRequest(ctx context.Context, userID string) (*model.FriendRequest, error) {
+	return r.FriendService.SendFriendRequest(ctx, userID)
+}
+
+// AcceptFriendRequest is the resolver for the acceptFriendRequest field.
+func (r *mutationsResolver) AcceptFriendRequest(ctx context.Context, userID string) (*model.Friend, error) {
+	return r.FriendService.AcceptFriendRequest(ctx, userID)
+}
+
+// DeclineFriendRequest is the resolver for the declineFriendRequest field.
+func (r *mutationsResolver) DeclineFriendRequest(ctx context.Context, userID string) (bool, error) {
+	if err := r.FriendService.DeclineFriendRequest(ctx, userID); err != nil {
+		return fail(err)
+	}
+
+	return success()
+}
+
+// CancelFriendRequest is the resolver for the cancelFriendRequest field.
+func (r *mutationsResolver) CancelFriendRequest(ctx context.Context, userID string) (bool, error) {
+	if err := r.FriendService.CancelFriendRequest(ctx, userID); err != nil {
+		return fail(err)
+	}
+
+	return success()
+}
+
+// Unfriend is the resolver for the unfriend field.
+func (r *mutationsResolver) Unfriend(ctx context.Context, userID string) (bool, error) {
+	if err := r.FriendService.Unfriend(ctx, userID); err != nil {
+		return fail(err)
+	}
+
+	return success()
+}
+
+// BlockUser is the resolver for the blockUser field.
+func (r *mutationsResolver) BlockUser(ctx context.Context, userID string) (bool, error) {
+	if err := r.FriendService.BlockUser(ctx, userID); err != nil {
+		return fail(err)
+	}
+
+	return success()
+}
+
+// UnblockUser is the resolver for the unblockUser field.
+func (r *mutationsResolver) UnblockUser(ctx context.Context, userID string) (bool, error) {
+	if err := r.FriendService.UnblockUser(ctx, userID); err != nil {
+		return fail(err)
+	}
+
+	return success()
+}
+
+// Friends is the resolver for the friends field.
+func (r *queriesResolver) Friends(ctx context.Context, first *int, after *string) (*model.FriendConnection, error) {
+	input := services.GetFriendsInput{
+		After: after,
+	}
+
+	if first != nil {
+		input.First = null.IntFrom(int64(*first)).Ptr()
+	}
+
+	return r.FriendService.GetFriends(ctx, input)
+}
+
+// IncomingFriendRequests is the resolver for the incomingFriendRequests field.
+func (r *queriesResolver) IncomingFriendRequests(ctx context.Context, first *int, after *string) (*model.FriendRequestConnection, error) {
+	input := services.GetFriendsInput{
+		After: after,
+	}
+
+	if first != nil {
+		input.First = null.IntFrom(int64(*first)).Ptr()
+	}
+
+	return r.FriendService.GetIncomingFriendRequests(ctx, input)
+}
+
+// OutgoingFriendRequests is the resolver for the outgoingFriendRequests field.
+func (r *queriesResolver) OutgoingFriendRequests(ctx context.Context, first *int, after *string) (*model.FriendRequestConnection, error) {
+	input := services.GetFriendsInput{
+		After: after,
+	}
+
+	if first != nil {
+		input.First = null.IntFrom(int64(*first)).Ptr()
+	}
+
+	return r.FriendService.GetOutgoingFriendRequests(ctx, input)
+}
+
+// BlockedUsers is the resolver for the blockedUsers field.
+func (r *queriesResolver) BlockedUsers(ctx context.Context, first *int, after *string) (*model.BlockedUserConnection, error) {
+	input := services.GetFriendsInput{
+		After: after,
+	}
+
+	if first != nil {
+		input.First = null.IntFrom(int64(*first)).Ptr()
+	}
+
+	return r.FriendService.GetBlockedUsers(ctx, input)
+}
+
+// FriendEvents is the resolver for the friendEvents field.
+func (r *subscriptionsResolver) FriendEvents(ctx context.Context) (<-chan *model.FriendEvent, error) {
+	return r.FriendService.SubscribeToFriendEvents(ctx)
+}
+
+// BlockedUser returns generated.BlockedUserResolver implementation.
+func (r *Resolver) BlockedUser() generated.BlockedUserResolver { return &blockedUserResolver{r} }
+
+// Friend returns generated.FriendResolver implementation.
+func (r *Resolver) Friend() generated.FriendResolver { return &friendResolver{r} }
+
+// FriendRequest returns generated.FriendRequestResolver implementation.
+func (r *Resolver) FriendRequest() generated.FriendRequestResolver { return &friendRequestResolver{r} }
+
+type blockedUserResolver struct{ *Resolver }
+type friendResolver struct{ *Resolver }
+type friendRequestResolver struct{ *Resolver }
@@ -6,26 +6,257 @@ package resolver
 
 import (
 	"context"
+	"time"
+
+	null "gopkg.in/guregu/null.v4"
 
 	"github.com/thanishsid/dingilink-server/api/graphql/generated"
 	"github.com/thanishsid/dingilink-server/internal/model"
+	"github.com/thanishsid/dingilink-server/internal/services"
+	"github.com/thanishsid/dingilink-server/internal/types/apperror"
 )
 
 // Members is the resolver for the members field.
 func (r *groupResolver) Members(ctx context.Context, obj *model.Group) ([]*model.GroupMember, error) {
+	isMember, err := r.isGroupMember(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isMember {
+		return nil, apperror.ErrForbidden
+	}
+
 	return r.Dataloader.GetGroupMembers(ctx, obj.ID)
 }
 
+// MembersConnection is the resolver for the membersConnection field.
+func (r *groupResolver) MembersConnection(ctx context.Context, obj *model.Group, first *int, after *string, search *string, adminsOnly *bool) (*model.GroupMemberConnection, error) {
+	input := services.GetGroupMembersInput{
+		After:      after,
+		Search:     search,
+		AdminsOnly: adminsOnly,
+	}
+
+	if first != nil {
+		input.First = null.IntFrom(int64(*first)).Ptr()
+	}
+
+	return r.GroupService.GetGroupMembers(ctx, obj.ID, input)
+}
+
+// MemberCount is the resolver for the memberCount field.
+func (r *groupResolver) MemberCount(ctx context.Context, obj *model.Group) (int, error) {
+	count, err := r.Dataloader.GetGroupMemberCount(ctx, obj.ID)
+	return int(count), err
+}
+
+// Group is the resolver for the group field.
+func (r *groupInviteResolver) Group(ctx context.Context, obj *model.GroupInvite) (*model.Group, error) {
+	return r.Dataloader.GetGroup(ctx, obj.GroupID)
+}
+
+// CreatedBy is the resolver for the createdBy field.
+func (r *groupInviteResolver) CreatedBy(ctx context.Context, obj *model.GroupInvite) (*model.User, error) {
+	return r.Dataloader.GetUser(ctx, obj.CreatedBy)
+}
+
+// ExpiresAt is the resolver for the expiresAt field.
+func (r *groupInviteResolver) ExpiresAt(ctx context.Context, obj *model.GroupInvite) (*time.Time, error) {
+	return null.NewTime(obj.ExpiresAt.Time, obj.ExpiresAt.Valid).Ptr(), nil
+}
+
+// RevokedAt is the resolver for the revokedAt field.
+func (r *groupInviteResolver) RevokedAt(ctx context.Context, obj *model.GroupInvite) (*time.Time, error) {
+	return null.NewTime(obj.RevokedAt.Time, obj.RevokedAt.Valid).Ptr(), nil
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *groupInviteResolver) CreatedAt(ctx context.Context, obj *model.GroupInvite) (*time.Time, error) {
+	return null.NewTime(obj.CreatedAt.Time, obj.CreatedAt.Valid).Ptr(), nil
+}
+
+// Uses is the resolver for the uses field.
+func (r *groupInviteResolver) Uses(ctx context.Context, obj *model.GroupInvite) ([]*model.GroupInviteUse, error) {
+	return r.Dataloader.GetGroupInviteUses(ctx, obj.ID)
+}
+
+// User is the resolver for the user field.
+func (r *groupInviteUseResolver) User(ctx context.Context, obj *model.GroupInviteUse) (*model.User, error) {
+	return r.Dataloader.GetUser(ctx, obj.UserID)
+}
+
+// UsedAt is the resolver for the usedAt field.
+func (r *groupInviteUseResolver) UsedAt(ctx context.Context, obj *model.GroupInviteUse) (*time.Time, error) {
+	return null.NewTime(obj.UsedAt.Time, obj.UsedAt.Valid).Ptr(), nil
+}
+
+// User is the resolver for the user field.
+func (r *groupJoinRequestResolver) User(ctx context.Context, obj *model.GroupJoinRequest) (*model.User, error) {
+	return r.Dataloader.GetUser(ctx, obj.UserID)
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *groupJoinRequestResolver) CreatedAt(ctx context.Context, obj *model.GroupJoinRequest) (*time.Time, error) {
+	return null.NewTime(obj.CreatedAt.Time, obj.CreatedAt.Valid).Ptr(), nil
+}
+
+// DecidedBy is the resolver for the decidedBy field.
+func (r *groupJoinRequestResolver) DecidedBy(ctx context.Context, obj *model.GroupJoinRequest) (*model.User, error) {
+	if obj.DecidedBy == nil {
+		return nil, nil
+	}
+
+	return r.Dataloader.GetUser(ctx, *obj.DecidedBy)
+}
+
+// DecidedAt is the resolver for the decidedAt field.
+func (r *groupJoinRequestResolver) DecidedAt(ctx context.Context, obj *model.GroupJoinRequest) (*time.Time, error) {
+	return null.NewTime(obj.DecidedAt.Time, obj.DecidedAt.Valid).Ptr(), nil
+}
+
 // User is the resolver for the user field.
 func (r *groupMemberResolver) User(ctx context.Context, obj *model.GroupMember) (*model.User, error) {
 	return r.Dataloader.GetUser(ctx, obj.UserID)
 }
 
+// CreateGroup is the resolver for the createGroup field.
+func (r *mutationsResolver) CreateGroup(ctx context.Context, input services.CreateGroupInput) (*model.Group, error) {
+	return r.GroupService.CreateGroup(ctx, input)
+}
+
+// UpdateGroup is the resolver for the updateGroup field.
+func (r *mutationsResolver) UpdateGroup(ctx context.Context, input services.UpdateGroupInput) (*model.Group, error) {
+	return r.GroupService.UpdateGroup(ctx, input)
+}
+
+// UpdateGroupPolicies is the resolver for the updateGroupPolicies field.
+func (r *mutationsResolver) UpdateGroupPolicies(ctx context.Context, input services.UpdateGroupPoliciesInput) (*model.Group, error) {
+	return r.GroupService.UpdateGroupPolicies(ctx, input)
+}
+
+// DeleteGroup is the resolver for the deleteGroup field.
+func (r *mutationsResolver) DeleteGroup(ctx context.Context, groupID string) (bool, error) {
+	if err := r.GroupService.DeleteGroup(ctx, groupID); err != nil {
+		return fail(err)
+	}
+
+	return success()
+}
+
+// AddGroupMembers is the resolver for the addGroupMembers field.
+func (r *mutationsResolver) AddGroupMembers(ctx context.Context, input services.AddGroupMembersInput) (*model.Group, error) {
+	return r.GroupService.AddGroupMembers(ctx, input)
+}
+
+// RemoveGroupMember is the resolver for the removeGroupMember field.
+func (r *mutationsResolver) RemoveGroupMember(ctx context.Context, groupID string, userID string) (bool, error) {
+	if err := r.GroupService.RemoveGroupMember(ctx, groupID, userID); err != nil {
+		return fail(err)
+	}
+
+	return success()
+}
+
+// SetGroupAdmin is the resolver for the setGroupAdmin field.
+func (r *mutationsResolver) SetGroupAdmin(ctx context.Context, input services.SetGroupAdminInput) (*model.Group, error) {
+	return r.GroupService.SetGroupAdmin(ctx, input)
+}
+
+// LeaveGroup is the resolver for the leaveGroup field.
+func (r *mutationsResolver) LeaveGroup(ctx context.Context, groupID string) (bool, error) {
+	if err := r.GroupService.LeaveGroup(ctx, groupID); err != nil {
+		return fail(err)
+	}
+
+	return success()
+}
+
+// TransferGroupOwnership is the resolver for the transferGroupOwnership field.
+func (r *mutationsResolver) TransferGroupOwnership(ctx context.Context, groupID string, userID string) (*model.Group, error) {
+	return r.GroupService.TransferGroupOwnership(ctx, groupID, userID)
+}
+
+// CreateGroupInvite is the resolver for the createGroupInvite field.
+func (r *mutationsResolver) CreateGroupInvite(ctx context.Context, input services.CreateGroupInviteInput) (*model.GroupInvite, error) {
+	return r.GroupService.CreateGroupInvite(ctx, input)
+}
+
+// RevokeGroupInvite is the resolver for the revokeGroupInvite field.
+func (r *mutationsResolver) RevokeGroupInvite(ctx context.Context, inviteID string) (bool, error) {
+	if err := r.GroupService.RevokeGroupInvite(ctx, inviteID); err != nil {
+		return fail(err)
+	}
+
+	return success()
+}
+
+// JoinGroupByInvite is the resolver for the joinGroupByInvite field.
+func (r *mutationsResolver) JoinGroupByInvite(ctx context.Context, code string) (*model.JoinGroupResult, error) {
+	return r.GroupService.JoinGroupByInvite(ctx, code)
+}
+
+// ApproveJoinRequest is the resolver for the approveJoinRequest field.
+func (r *mutationsResolver) ApproveJoinRequest(ctx context.Context, requestID string) (*model.GroupJoinRequest, error) {
+	return r.GroupService.ApproveJoinRequest(ctx, requestID)
+}
+
+// RejectJoinRequest is the resolver for the rejectJoinRequest field.
+func (r *mutationsResolver) RejectJoinRequest(ctx context.Context, requestID string) (*model.GroupJoinRequest, error) {
+	return r.GroupService.RejectJoinRequest(ctx, requestID)
+}
+
+// Group is the resolver for the group field.
+func (r *queriesResolver) Group(ctx context.Context, id string) (*model.Group, error) {
+	return r.GroupService.GetGroup(ctx, id)
+}
+
+// MyGroups is the resolver for the myGroups field.
+func (r *queriesResolver) MyGroups(ctx context.Context) ([]*model.Group, error) {
+	return r.GroupService.GetMyGroups(ctx)
+}
+
+// GroupInvites is the resolver for the groupInvites field.
+func (r *queriesResolver) GroupInvites(ctx context.Context, groupID string) ([]*model.GroupInvite, error) {
+	return r.GroupService.GetGroupInvites(ctx, groupID)
+}
+
+// GroupInvitePreview is the resolver for the groupInvitePreview field.
+func (r *queriesResolver) GroupInvitePreview(ctx context.Context, code string) (*model.GroupInvitePreview, error) {
+	return r.GroupService.GetGroupInvitePreview(ctx, code)
+}
+
+// PendingJoinRequests is the resolver for the pendingJoinRequests field.
+func (r *queriesResolver) PendingJoinRequests(ctx context.Context, groupID string) ([]*model.GroupJoinRequest, error) {
+	return r.GroupService.GetPendingJoinRequests(ctx, groupID)
+}
+
+// GroupEvents is the resolver for the groupEvents field.
+func (r *subscriptionsResolver) GroupEvents(ctx context.Context) (<-chan *model.GroupEvent, error) {
+	return r.GroupService.SubscribeToGroupEvents(ctx)
+}
+
 // Group returns generated.GroupResolver implementation.
 func (r *Resolver) Group() generated.GroupResolver { return &groupResolver{r} }
 
+// GroupInvite returns generated.GroupInviteResolver implementation.
+func (r *Resolver) GroupInvite() generated.GroupInviteResolver { return &groupInviteResolver{r} }
+
+// GroupInviteUse returns generated.GroupInviteUseResolver implementation.
+func (r *Resolver) GroupInviteUse() generated.GroupInviteUseResolver {
+	return &groupInviteUseResolver{r}
+}
+
+// GroupJoinRequest returns generated.GroupJoinRequestResolver implementation.
+func (r *Resolver) GroupJoinRequest() generated.GroupJoinRequestResolver {
+	return &groupJoinRequestResolver{r}
+}
+
 // GroupMember returns generated.GroupMemberResolver implementation.
 func (r *Resolver) GroupMember() generated.GroupMemberResolver { return &groupMemberResolver{r} }
 
 type groupResolver struct{ *Resolver }
+type groupInviteResolver struct{ *Resolver }
+type groupInviteUseResolver struct{ *Resolver }
+type groupJoinRequestResolver struct{ *Resolver }
 type groupMemberResolver struct{ *Resolver }
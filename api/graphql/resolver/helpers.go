@@ -1,5 +1,11 @@
 package resolver
 
+import (
+	"context"
+
+	"github.com/thanishsid/dingilink-server/internal/pkg/security"
+)
+
 func fail(err error) (bool, error) {
 	return false, err
 }
@@ -8,6 +14,26 @@ func success() (bool, error) {
 	return true, nil
 }
 
+// Check if the current user and the given user have blocked each other in either direction.
+func (r *Resolver) isBlockedWith(ctx context.Context, userID int64) (bool, error) {
+	userInfo, err := security.GetUserInfo(ctx)
+	if err != nil || !userInfo.Authenticated || userInfo.User.ID == userID {
+		return false, nil
+	}
+
+	return r.Dataloader.GetUsersBlocked(ctx, userInfo.User.ID, userID)
+}
+
+// Check if the current user is a member of the given group.
+func (r *Resolver) isGroupMember(ctx context.Context, groupID int64) (bool, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return false, err
+	}
+
+	return r.Dataloader.GetIsGroupMember(ctx, groupID, userInfo.User.ID)
+}
+
 // func NullDecimalToStringPtr(dec decimal.NullDecimal) *string {
 // 	if dec.Valid {
 // 		str := dec.Decimal.String()
@@ -143,7 +143,11 @@ func (r *locationMessageResolver) SentAt(ctx context.Context, obj *model.Locatio
 
 // Message is the resolver for the message field.
 func (r *messageEventResolver) Message(ctx context.Context, obj *model.MessageEvent) (model.Message, error) {
-	return r.Dataloader.GetMessage(ctx, obj.MessageID)
+	if obj.Payload != nil {
+		return obj.Payload.Build()
+	}
+
+	return r.MessageService.GetMessage(ctx, obj.MessageID)
 }
 
 // SendMessage is the resolver for the sendMessage field.
@@ -151,6 +155,65 @@ func (r *mutationsResolver) SendMessage(ctx context.Context, input services.Send
 	return r.MessageService.SendMessage(ctx, input)
 }
 
+// Vote is the resolver for the vote field.
+func (r *mutationsResolver) Vote(ctx context.Context, input services.VoteInput) (model.Message, error) {
+	return r.MessageService.Vote(ctx, input)
+}
+
+// RetractVote is the resolver for the retractVote field.
+func (r *mutationsResolver) RetractVote(ctx context.Context, input services.RetractVoteInput) (model.Message, error) {
+	return r.MessageService.RetractVote(ctx, input)
+}
+
+// ClosesAt is the resolver for the closesAt field.
+func (r *pollResolver) ClosesAt(ctx context.Context, obj *model.Poll) (*time.Time, error) {
+	return null.NewTime(obj.ClosesAt.Time, obj.ClosesAt.Valid).Ptr(), nil
+}
+
+// Sender is the resolver for the sender field.
+func (r *pollMessageResolver) Sender(ctx context.Context, obj *model.PollMessage) (*model.User, error) {
+	return r.Dataloader.GetUser(ctx, obj.SenderID)
+}
+
+// Group is the resolver for the group field.
+func (r *pollMessageResolver) Group(ctx context.Context, obj *model.PollMessage) (*model.Group, error) {
+	if obj.GroupID == nil {
+		return nil, nil
+	}
+
+	return r.Dataloader.GetGroup(ctx, *obj.GroupID)
+}
+
+// Poll is the resolver for the poll field.
+func (r *pollMessageResolver) Poll(ctx context.Context, obj *model.PollMessage) (*model.Poll, error) {
+	return r.Dataloader.GetPoll(ctx, obj.ID)
+}
+
+// SentAt is the resolver for the sentAt field.
+func (r *pollMessageResolver) SentAt(ctx context.Context, obj *model.PollMessage) (*time.Time, error) {
+	return null.NewTime(obj.SentAt.Time, obj.SentAt.Valid).Ptr(), nil
+}
+
+// Voters is the resolver for the voters field.
+func (r *pollOptionResolver) Voters(ctx context.Context, obj *model.PollOption) ([]*model.User, error) {
+	if obj.Anonymous {
+		return nil, nil
+	}
+
+	voters := make([]*model.User, len(obj.VoterIDs))
+
+	for idx, id := range obj.VoterIDs {
+		user, err := r.Dataloader.GetUser(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		voters[idx] = user
+	}
+
+	return voters, nil
+}
+
 // Messages is the resolver for the messages field.
 func (r *queriesResolver) Messages(ctx context.Context, chatID string, input *services.GetMessagesInput) (*model.MessageConnection, error) {
 	var i services.GetMessagesInput
@@ -163,8 +226,43 @@ func (r *queriesResolver) Messages(ctx context.Context, chatID string, input *se
 }
 
 // MessageEvents is the resolver for the messageEvents field.
-func (r *subscriptionsResolver) MessageEvents(ctx context.Context) (<-chan *model.MessageEvent, error) {
-	panic(fmt.Errorf("not implemented: MessageEvents - messageEvents"))
+func (r *subscriptionsResolver) MessageEvents(ctx context.Context, chatID *string, since *string) (<-chan *model.MessageEvent, error) {
+	return r.MessageService.SubscribeToMessageEvents(ctx, chatID, since)
+}
+
+// Sender is the resolver for the sender field.
+func (r *systemMessageResolver) Sender(ctx context.Context, obj *model.SystemMessage) (*model.User, error) {
+	return r.Dataloader.GetUser(ctx, obj.SenderID)
+}
+
+// Group is the resolver for the group field.
+func (r *systemMessageResolver) Group(ctx context.Context, obj *model.SystemMessage) (*model.Group, error) {
+	if obj.GroupID == nil {
+		return nil, nil
+	}
+
+	return r.Dataloader.GetGroup(ctx, *obj.GroupID)
+}
+
+// Targets is the resolver for the targets field.
+func (r *systemMessageResolver) Targets(ctx context.Context, obj *model.SystemMessage) ([]*model.User, error) {
+	targets := make([]*model.User, len(obj.Payload.TargetUserIDs))
+
+	for idx, id := range obj.Payload.TargetUserIDs {
+		user, err := r.Dataloader.GetUser(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		targets[idx] = user
+	}
+
+	return targets, nil
+}
+
+// SentAt is the resolver for the sentAt field.
+func (r *systemMessageResolver) SentAt(ctx context.Context, obj *model.SystemMessage) (*time.Time, error) {
+	return null.NewTime(obj.SentAt.Time, obj.SentAt.Valid).Ptr(), nil
 }
 
 // Sender is the resolver for the sender field.
@@ -245,6 +343,18 @@ func (r *Resolver) LocationMessage() generated.LocationMessageResolver {
 // MessageEvent returns generated.MessageEventResolver implementation.
 func (r *Resolver) MessageEvent() generated.MessageEventResolver { return &messageEventResolver{r} }
 
+// Poll returns generated.PollResolver implementation.
+func (r *Resolver) Poll() generated.PollResolver { return &pollResolver{r} }
+
+// PollMessage returns generated.PollMessageResolver implementation.
+func (r *Resolver) PollMessage() generated.PollMessageResolver { return &pollMessageResolver{r} }
+
+// PollOption returns generated.PollOptionResolver implementation.
+func (r *Resolver) PollOption() generated.PollOptionResolver { return &pollOptionResolver{r} }
+
+// SystemMessage returns generated.SystemMessageResolver implementation.
+func (r *Resolver) SystemMessage() generated.SystemMessageResolver { return &systemMessageResolver{r} }
+
 // TextMessage returns generated.TextMessageResolver implementation.
 func (r *Resolver) TextMessage() generated.TextMessageResolver { return &textMessageResolver{r} }
 
@@ -262,6 +372,10 @@ type documentMessageResolver struct{ *Resolver }
 type imageMessageResolver struct{ *Resolver }
 type locationMessageResolver struct{ *Resolver }
 type messageEventResolver struct{ *Resolver }
+type pollResolver struct{ *Resolver }
+type pollMessageResolver struct{ *Resolver }
+type pollOptionResolver struct{ *Resolver }
+type systemMessageResolver struct{ *Resolver }
 type textMessageResolver struct{ *Resolver }
 type videoMessageResolver struct{ *Resolver }
 type sendMessageInputResolver struct{ *Resolver }
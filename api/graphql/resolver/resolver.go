@@ -13,6 +13,10 @@ type Resolver struct {
 	// Services
 	UserService    *services.UserService
 	MessageService *services.MessageService
+	ExportService  *services.ExportService
+	GroupService   *services.GroupService
+	ChannelService *services.ChannelService
+	FriendService  *services.FriendService
 
 	// Dataloader
 	Dataloader *dtloader.Dataloader
@@ -20,6 +20,10 @@ type HandlerConfig struct {
 	UploadService  *services.UploadService
 	UserService    *services.UserService
 	MessageService *services.MessageService
+	ExportService  *services.ExportService
+	GroupService   *services.GroupService
+	ChannelService *services.ChannelService
+	FriendService  *services.FriendService
 
 	PG db.DBQ
 	TC tokenizer.Config
@@ -32,6 +36,10 @@ func NewHandler(hc *HandlerConfig) http.Handler {
 	gqlHandler := graphql.NewHandler(&resolver.Resolver{
 		UserService:    hc.UserService,
 		MessageService: hc.MessageService,
+		ExportService:  hc.ExportService,
+		GroupService:   hc.GroupService,
+		ChannelService: hc.ChannelService,
+		FriendService:  hc.FriendService,
 		Dataloader:     dataloader,
 	}, hc.TC, hc.PG)
 
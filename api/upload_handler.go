@@ -21,6 +21,7 @@ func CreateUploadHandler(us *services.UploadService) http.HandlerFunc {
 		defer file.Close()
 
 		generateThumbnail := r.FormValue("generateThumbnail")
+		voiceNote := r.FormValue("voiceNote")
 		thumbnailPosition, posErr := strconv.ParseFloat(r.FormValue("thumbnailPosition"), 64)
 
 		input := services.UploadFileInput{
@@ -28,6 +29,7 @@ func CreateUploadHandler(us *services.UploadService) http.HandlerFunc {
 			FileHeader:        fh,
 			GenerateThumbnail: null.NewBool(true, generateThumbnail == "true").Ptr(),
 			ThumbnailPosition: null.NewFloat(thumbnailPosition, posErr == nil).Ptr(),
+			VoiceNote:         null.NewBool(true, voiceNote == "true").Ptr(),
 		}
 
 		result, err := us.UploadFile(r.Context(), input)
@@ -6,17 +6,25 @@ import (
 	"log"
 )
 
-//go:embed email-templates/*.html
+//go:embed email-templates/*.html export-templates/*.html
 var files embed.FS
 
 var MailTemplates *template.Template
 
+var ExportTemplates *template.Template
+
 func init() {
 	tmpl, err := template.ParseFS(files, "email-templates/*.html")
 	if err != nil {
 		log.Fatal(err)
 	}
 	MailTemplates = tmpl
+
+	exportTmpl, err := template.ParseFS(files, "export-templates/*.html")
+	if err != nil {
+		log.Fatal(err)
+	}
+	ExportTemplates = exportTmpl
 }
 
 func GetFs() embed.FS {
@@ -0,0 +1,64 @@
+// Backfills the conversations and conversation members tables from the existing messages.
+// It is safe to run multiple times, existing conversations are updated with their latest message.
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/thanishsid/dingilink-server/internal/config"
+	"github.com/thanishsid/dingilink-server/internal/db"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill)
+	defer stop()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pgconn, err := db.ConnectPool(ctx, cfg.DBConnectionString)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer pgconn.Close()
+
+	pg := db.NewDBQuerier(pgconn)
+
+	tx, err := pg.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Conversations must exist before their members, and members before their unread counts.
+	steps := []struct {
+		name string
+		run  func(ctx context.Context) (int64, error)
+	}{
+		{"group conversations", tx.BackfillGroupConversations},
+		{"direct conversations", tx.BackfillDirectConversations},
+		{"group conversation members", tx.BackfillGroupConversationMembers},
+		{"direct conversation members", tx.BackfillDirectConversationMembers},
+		{"conversation unread counts", tx.BackfillConversationUnreadCounts},
+	}
+
+	for _, step := range steps {
+		rows, err := step.run(ctx)
+		if err != nil {
+			log.Fatalf("failed to backfill %s: %v", step.name, err)
+		}
+
+		log.Printf("backfilled %s: %d rows", step.name, rows)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Fatal(err)
+	}
+}
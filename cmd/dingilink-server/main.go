@@ -77,6 +77,31 @@ func main() {
 		log.Fatal(err)
 	}
 
+	draftEventChannelManager, err := messaging.NewChannelManager[*model.DraftEvent](cfg.NatsUrl)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	chatListEventChannelManager, err := messaging.NewChannelManager[*model.ChatListEvent](cfg.NatsUrl)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	groupEventChannelManager, err := messaging.NewChannelManager[*model.GroupEvent](cfg.NatsUrl)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	channelEventChannelManager, err := messaging.NewChannelManager[*model.ChannelEvent](cfg.NatsUrl)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	friendEventChannelManager, err := messaging.NewChannelManager[*model.FriendEvent](cfg.NatsUrl)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	uploadService := &services.UploadService{
 		S3Client:  s3Client,
 		S3Bucket:  cfg.S3Bucket,
@@ -93,8 +118,31 @@ func main() {
 	}
 
 	messageService := &services.MessageService{
+		DB:         pg,
+		CH:         messageEventchannelManager,
+		DraftCH:    draftEventChannelManager,
+		ChatListCH: chatListEventChannelManager,
+	}
+
+	exportService := &services.ExportService{
+		DB:     pg,
+		Upload: uploadService,
+	}
+
+	groupService := &services.GroupService{
+		DB:      pg,
+		CH:      groupEventChannelManager,
+		Message: messageService,
+	}
+
+	channelService := &services.ChannelService{
+		DB: pg,
+		CH: channelEventChannelManager,
+	}
+
+	friendService := &services.FriendService{
 		DB: pg,
-		CH: messageEventchannelManager,
+		CH: friendEventChannelManager,
 	}
 
 	h := api.NewHandler(
@@ -102,6 +150,10 @@ func main() {
 			UploadService:  uploadService,
 			UserService:    userService,
 			MessageService: messageService,
+			ExportService:  exportService,
+			GroupService:   groupService,
+			ChannelService: channelService,
+			FriendService:  friendService,
 		},
 	)
 
@@ -121,6 +173,11 @@ func main() {
 		return srv.ListenAndServe()
 	})
 
+	// Remove expired message events from the events log in a separate goroutine.
+	g.Go(func() error {
+		return messageService.PruneMessageEvents(gCtx)
+	})
+
 	// Listen for context cancellation in seprate goroutine and call server shutdown.
 	g.Go(func() error {
 		<-gCtx.Done()
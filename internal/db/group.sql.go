@@ -11,6 +11,82 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const CheckGroupMember = `-- name: CheckGroupMember :one
+SELECT EXISTS(SELECT 1 FROM group_members WHERE group_id = $1 AND user_id = $2)
+`
+
+type CheckGroupMemberParams struct {
+	GroupID int64
+	UserID  int64
+}
+
+func (q *Queries) CheckGroupMember(ctx context.Context, arg CheckGroupMemberParams) (bool, error) {
+	row := q.db.QueryRow(ctx, CheckGroupMember, arg.GroupID, arg.UserID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const DeleteGroup = `-- name: DeleteGroup :exec
+DELETE FROM groups WHERE id = $1
+`
+
+func (q *Queries) DeleteGroup(ctx context.Context, groupID int64) error {
+	_, err := q.db.Exec(ctx, DeleteGroup, groupID)
+	return err
+}
+
+const DeleteGroupMember = `-- name: DeleteGroupMember :execrows
+DELETE FROM group_members WHERE group_id = $1 AND user_id = $2
+`
+
+type DeleteGroupMemberParams struct {
+	GroupID int64
+	UserID  int64
+}
+
+func (q *Queries) DeleteGroupMember(ctx context.Context, arg DeleteGroupMemberParams) (int64, error) {
+	result, err := q.db.Exec(ctx, DeleteGroupMember, arg.GroupID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const GetBatchedGroupMemberCounts = `-- name: GetBatchedGroupMemberCounts :many
+SELECT
+    group_id,
+    COUNT(*) AS member_count
+FROM group_members
+WHERE group_id = ANY($1::BIGINT[])
+GROUP BY group_id
+`
+
+type GetBatchedGroupMemberCountsRow struct {
+	GroupID     int64
+	MemberCount int64
+}
+
+func (q *Queries) GetBatchedGroupMemberCounts(ctx context.Context, groupIds []int64) ([]GetBatchedGroupMemberCountsRow, error) {
+	rows, err := q.db.Query(ctx, GetBatchedGroupMemberCounts, groupIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetBatchedGroupMemberCountsRow
+	for rows.Next() {
+		var i GetBatchedGroupMemberCountsRow
+		if err := rows.Scan(&i.GroupID, &i.MemberCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const GetBatchedGroupMembers = `-- name: GetBatchedGroupMembers :many
 SELECT 
     gm.id, gm.group_id, gm.user_id, gm.joined_at, gm.is_admin,
@@ -59,8 +135,49 @@ func (q *Queries) GetBatchedGroupMembers(ctx context.Context, groupIds []int64)
 	return items, nil
 }
 
+const GetBatchedGroupMemberships = `-- name: GetBatchedGroupMemberships :many
+SELECT
+    p.group_id::BIGINT AS group_id,
+    p.user_id::BIGINT AS user_id
+FROM UNNEST($1::BIGINT[], $2::BIGINT[]) AS p (group_id, user_id)
+WHERE EXISTS(
+    SELECT 1 FROM group_members gm
+    WHERE gm.group_id = p.group_id AND gm.user_id = p.user_id
+)
+`
+
+type GetBatchedGroupMembershipsParams struct {
+	GroupIds []int64
+	UserIds  []int64
+}
+
+type GetBatchedGroupMembershipsRow struct {
+	GroupID int64
+	UserID  int64
+}
+
+func (q *Queries) GetBatchedGroupMemberships(ctx context.Context, arg GetBatchedGroupMembershipsParams) ([]GetBatchedGroupMembershipsRow, error) {
+	rows, err := q.db.Query(ctx, GetBatchedGroupMemberships, arg.GroupIds, arg.UserIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetBatchedGroupMembershipsRow
+	for rows.Next() {
+		var i GetBatchedGroupMembershipsRow
+		if err := rows.Scan(&i.GroupID, &i.UserID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const GetBatchedGroups = `-- name: GetBatchedGroups :many
-SELECT id, name, image, description, created_by, created_at FROM groups WHERE id = ANY($1::BIGINT[])
+SELECT id, name, image, description, created_by, created_at, join_policy, only_admins_can_send_messages, only_admins_can_edit_info, only_admins_can_add_members FROM groups WHERE id = ANY($1::BIGINT[])
 `
 
 func (q *Queries) GetBatchedGroups(ctx context.Context, groupIds []int64) ([]Group, error) {
@@ -79,6 +196,10 @@ func (q *Queries) GetBatchedGroups(ctx context.Context, groupIds []int64) ([]Gro
 			&i.Description,
 			&i.CreatedBy,
 			&i.CreatedAt,
+			&i.JoinPolicy,
+			&i.OnlyAdminsCanSendMessages,
+			&i.OnlyAdminsCanEditInfo,
+			&i.OnlyAdminsCanAddMembers,
 		); err != nil {
 			return nil, err
 		}
@@ -91,7 +212,7 @@ func (q *Queries) GetBatchedGroups(ctx context.Context, groupIds []int64) ([]Gro
 }
 
 const GetGroupByID = `-- name: GetGroupByID :one
-SELECT id, name, image, description, created_by, created_at FROM groups WHERE id = $1
+SELECT id, name, image, description, created_by, created_at, join_policy, only_admins_can_send_messages, only_admins_can_edit_info, only_admins_can_add_members FROM groups WHERE id = $1
 `
 
 func (q *Queries) GetGroupByID(ctx context.Context, groupID int64) (Group, error) {
@@ -104,10 +225,47 @@ func (q *Queries) GetGroupByID(ctx context.Context, groupID int64) (Group, error
 		&i.Description,
 		&i.CreatedBy,
 		&i.CreatedAt,
+		&i.JoinPolicy,
+		&i.OnlyAdminsCanSendMessages,
+		&i.OnlyAdminsCanEditInfo,
+		&i.OnlyAdminsCanAddMembers,
 	)
 	return i, err
 }
 
+const GetGroupMember = `-- name: GetGroupMember :one
+SELECT id, group_id, user_id, joined_at, is_admin FROM group_members WHERE group_id = $1 AND user_id = $2
+`
+
+type GetGroupMemberParams struct {
+	GroupID int64
+	UserID  int64
+}
+
+func (q *Queries) GetGroupMember(ctx context.Context, arg GetGroupMemberParams) (GroupMember, error) {
+	row := q.db.QueryRow(ctx, GetGroupMember, arg.GroupID, arg.UserID)
+	var i GroupMember
+	err := row.Scan(
+		&i.ID,
+		&i.GroupID,
+		&i.UserID,
+		&i.JoinedAt,
+		&i.IsAdmin,
+	)
+	return i, err
+}
+
+const GetGroupMemberCount = `-- name: GetGroupMemberCount :one
+SELECT COUNT(*) FROM group_members WHERE group_id = $1
+`
+
+func (q *Queries) GetGroupMemberCount(ctx context.Context, groupID int64) (int64, error) {
+	row := q.db.QueryRow(ctx, GetGroupMemberCount, groupID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const GetGroupMembers = `-- name: GetGroupMembers :many
 SELECT id, group_id, user_id, joined_at, is_admin FROM group_members WHERE group_id = $1
 `
@@ -137,3 +295,301 @@ func (q *Queries) GetGroupMembers(ctx context.Context, groupID int64) ([]GroupMe
 	}
 	return items, nil
 }
+
+const GetGroupMembersPage = `-- name: GetGroupMembersPage :many
+SELECT
+    gm.id, gm.group_id, gm.user_id, gm.joined_at, gm.is_admin,
+    (g.created_by = gm.user_id) AS is_owner
+FROM group_members gm
+JOIN groups g ON g.id = gm.group_id
+JOIN users u ON u.id = gm.user_id
+WHERE gm.group_id = $1
+    AND (NOT $2::BOOLEAN OR gm.is_admin)
+    AND ($3::TEXT IS NULL OR u.name ILIKE '%' || $3::TEXT || '%' ESCAPE '\' OR u.username ILIKE $3::TEXT || '%' ESCAPE '\')
+    AND (
+        $4::BIGINT IS NULL
+        OR (gm.joined_at, gm.id) > ($5::TIMESTAMPTZ, $4::BIGINT)
+    )
+ORDER BY
+    gm.joined_at ASC,
+    gm.id ASC
+LIMIT $6
+`
+
+type GetGroupMembersPageParams struct {
+	GroupID        int64
+	AdminsOnly     bool
+	Search         *string
+	CursorID       *int64
+	CursorJoinedAt pgtype.Timestamptz
+	ResultLimit    int64
+}
+
+type GetGroupMembersPageRow struct {
+	ID       int64
+	GroupID  int64
+	UserID   int64
+	JoinedAt pgtype.Timestamptz
+	IsAdmin  bool
+	IsOwner  bool
+}
+
+func (q *Queries) GetGroupMembersPage(ctx context.Context, arg GetGroupMembersPageParams) ([]GetGroupMembersPageRow, error) {
+	rows, err := q.db.Query(ctx, GetGroupMembersPage,
+		arg.GroupID,
+		arg.AdminsOnly,
+		arg.Search,
+		arg.CursorID,
+		arg.CursorJoinedAt,
+		arg.ResultLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetGroupMembersPageRow
+	for rows.Next() {
+		var i GetGroupMembersPageRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.GroupID,
+			&i.UserID,
+			&i.JoinedAt,
+			&i.IsAdmin,
+			&i.IsOwner,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const GetUserGroups = `-- name: GetUserGroups :many
+SELECT g.id, g.name, g.image, g.description, g.created_by, g.created_at, g.join_policy, g.only_admins_can_send_messages, g.only_admins_can_edit_info, g.only_admins_can_add_members
+FROM groups g
+JOIN group_members gm ON gm.group_id = g.id
+WHERE gm.user_id = $1
+ORDER BY g.name ASC
+`
+
+func (q *Queries) GetUserGroups(ctx context.Context, userID int64) ([]Group, error) {
+	rows, err := q.db.Query(ctx, GetUserGroups, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Group
+	for rows.Next() {
+		var i Group
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Image,
+			&i.Description,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.JoinPolicy,
+			&i.OnlyAdminsCanSendMessages,
+			&i.OnlyAdminsCanEditInfo,
+			&i.OnlyAdminsCanAddMembers,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const InsertGroup = `-- name: InsertGroup :one
+INSERT INTO groups (
+    name,
+    description,
+    image,
+    created_by
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+) RETURNING id, name, image, description, created_by, created_at, join_policy, only_admins_can_send_messages, only_admins_can_edit_info, only_admins_can_add_members
+`
+
+type InsertGroupParams struct {
+	Name        string
+	Description *string
+	Image       *string
+	CreatedBy   int64
+}
+
+func (q *Queries) InsertGroup(ctx context.Context, arg InsertGroupParams) (Group, error) {
+	row := q.db.QueryRow(ctx, InsertGroup,
+		arg.Name,
+		arg.Description,
+		arg.Image,
+		arg.CreatedBy,
+	)
+	var i Group
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Image,
+		&i.Description,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.JoinPolicy,
+		&i.OnlyAdminsCanSendMessages,
+		&i.OnlyAdminsCanEditInfo,
+		&i.OnlyAdminsCanAddMembers,
+	)
+	return i, err
+}
+
+const InsertGroupMember = `-- name: InsertGroupMember :execrows
+INSERT INTO group_members (
+    group_id,
+    user_id,
+    is_admin
+) VALUES (
+    $1,
+    $2,
+    $3
+) ON CONFLICT (group_id, user_id) DO NOTHING
+`
+
+type InsertGroupMemberParams struct {
+	GroupID int64
+	UserID  int64
+	IsAdmin bool
+}
+
+func (q *Queries) InsertGroupMember(ctx context.Context, arg InsertGroupMemberParams) (int64, error) {
+	result, err := q.db.Exec(ctx, InsertGroupMember, arg.GroupID, arg.UserID, arg.IsAdmin)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const SetGroupMemberAdmin = `-- name: SetGroupMemberAdmin :execrows
+UPDATE group_members SET is_admin = $1 WHERE group_id = $2 AND user_id = $3
+`
+
+type SetGroupMemberAdminParams struct {
+	IsAdmin bool
+	GroupID int64
+	UserID  int64
+}
+
+func (q *Queries) SetGroupMemberAdmin(ctx context.Context, arg SetGroupMemberAdminParams) (int64, error) {
+	result, err := q.db.Exec(ctx, SetGroupMemberAdmin, arg.IsAdmin, arg.GroupID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const UpdateGroup = `-- name: UpdateGroup :one
+UPDATE groups SET
+    name = $1,
+    description = CASE WHEN $2::BOOLEAN THEN NULL ELSE COALESCE($3, description) END,
+    image = CASE WHEN $4::BOOLEAN THEN NULL ELSE COALESCE($5, image) END,
+    join_policy = $6
+WHERE id = $7
+RETURNING id, name, image, description, created_by, created_at, join_policy, only_admins_can_send_messages, only_admins_can_edit_info, only_admins_can_add_members
+`
+
+type UpdateGroupParams struct {
+	Name             string
+	ClearDescription bool
+	Description      *string
+	ClearImage       bool
+	Image            *string
+	JoinPolicy       string
+	GroupID          int64
+}
+
+func (q *Queries) UpdateGroup(ctx context.Context, arg UpdateGroupParams) (Group, error) {
+	row := q.db.QueryRow(ctx, UpdateGroup,
+		arg.Name,
+		arg.ClearDescription,
+		arg.Description,
+		arg.ClearImage,
+		arg.Image,
+		arg.JoinPolicy,
+		arg.GroupID,
+	)
+	var i Group
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Image,
+		&i.Description,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.JoinPolicy,
+		&i.OnlyAdminsCanSendMessages,
+		&i.OnlyAdminsCanEditInfo,
+		&i.OnlyAdminsCanAddMembers,
+	)
+	return i, err
+}
+
+const UpdateGroupOwner = `-- name: UpdateGroupOwner :exec
+UPDATE groups SET created_by = $1 WHERE id = $2
+`
+
+type UpdateGroupOwnerParams struct {
+	OwnerID int64
+	GroupID int64
+}
+
+func (q *Queries) UpdateGroupOwner(ctx context.Context, arg UpdateGroupOwnerParams) error {
+	_, err := q.db.Exec(ctx, UpdateGroupOwner, arg.OwnerID, arg.GroupID)
+	return err
+}
+
+const UpdateGroupPolicies = `-- name: UpdateGroupPolicies :one
+UPDATE groups SET
+    only_admins_can_send_messages = $1,
+    only_admins_can_edit_info = $2,
+    only_admins_can_add_members = $3
+WHERE id = $4
+RETURNING id, name, image, description, created_by, created_at, join_policy, only_admins_can_send_messages, only_admins_can_edit_info, only_admins_can_add_members
+`
+
+type UpdateGroupPoliciesParams struct {
+	OnlyAdminsCanSendMessages bool
+	OnlyAdminsCanEditInfo     bool
+	OnlyAdminsCanAddMembers   bool
+	GroupID                   int64
+}
+
+func (q *Queries) UpdateGroupPolicies(ctx context.Context, arg UpdateGroupPoliciesParams) (Group, error) {
+	row := q.db.QueryRow(ctx, UpdateGroupPolicies,
+		arg.OnlyAdminsCanSendMessages,
+		arg.OnlyAdminsCanEditInfo,
+		arg.OnlyAdminsCanAddMembers,
+		arg.GroupID,
+	)
+	var i Group
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Image,
+		&i.Description,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.JoinPolicy,
+		&i.OnlyAdminsCanSendMessages,
+		&i.OnlyAdminsCanEditInfo,
+		&i.OnlyAdminsCanAddMembers,
+	)
+	return i, err
+}
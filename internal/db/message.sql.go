@@ -8,83 +8,13 @@ package db
 import (
 	"context"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/thanishsid/dingilink-server/internal/types"
 )
 
-const CheckMessagesHasNextPage = `-- name: CheckMessagesHasNextPage :one
-SELECT EXISTS(
-    SELECT 1 FROM messages m 
-    WHERE
-        CASE 
-            WHEN $1::BIGINT IS NOT NULL THEN
-                (m.sender_id = $2::BIGINT OR m.recipient_id = $2::BIGINT)
-                AND
-                (m.sender_id = $1::BIGINT OR m.recipient_id = $1::BIGINT)
-            WHEN $3::BIGINT IS NOT NULL THEN
-                m.group_id = $3::BIGINT
-        END
-        AND
-        (m.id < $4::BIGINT)
-)
-`
-
-type CheckMessagesHasNextPageParams struct {
-	TargetUserID  *int64
-	CurrentUserID int64
-	TargetGroupID *int64
-	CursorID      int64
-}
-
-func (q *Queries) CheckMessagesHasNextPage(ctx context.Context, arg CheckMessagesHasNextPageParams) (bool, error) {
-	row := q.db.QueryRow(ctx, CheckMessagesHasNextPage,
-		arg.TargetUserID,
-		arg.CurrentUserID,
-		arg.TargetGroupID,
-		arg.CursorID,
-	)
-	var exists bool
-	err := row.Scan(&exists)
-	return exists, err
-}
-
-const CheckMessagesHasPreviousPage = `-- name: CheckMessagesHasPreviousPage :one
-SELECT EXISTS(
-    SELECT 1 FROM messages m 
-    WHERE
-        CASE 
-            WHEN $1::BIGINT IS NOT NULL THEN
-                (m.sender_id = $2::BIGINT OR m.recipient_id = $2::BIGINT)
-                AND
-                (m.sender_id = $1::BIGINT OR m.recipient_id = $1::BIGINT)
-            WHEN $3::BIGINT IS NOT NULL THEN
-                m.group_id = $3::BIGINT
-        END
-        AND
-        (m.id > $4::BIGINT)
-)
-`
-
-type CheckMessagesHasPreviousPageParams struct {
-	TargetUserID  *int64
-	CurrentUserID int64
-	TargetGroupID *int64
-	CursorID      int64
-}
-
-func (q *Queries) CheckMessagesHasPreviousPage(ctx context.Context, arg CheckMessagesHasPreviousPageParams) (bool, error) {
-	row := q.db.QueryRow(ctx, CheckMessagesHasPreviousPage,
-		arg.TargetUserID,
-		arg.CurrentUserID,
-		arg.TargetGroupID,
-		arg.CursorID,
-	)
-	var exists bool
-	err := row.Scan(&exists)
-	return exists, err
-}
-
 const GetBatchedMessages = `-- name: GetBatchedMessages :many
-SELECT id, sender_id, recipient_id, group_id, message_type, text_content, media, location, reply_for_message_id, sent_at, deleted_at, deleted_by FROM messages WHERE id = ANY($1::BIGINT[])
+SELECT id, sender_id, recipient_id, group_id, message_type, text_content, media, location, reply_for_message_id, sent_at, deleted_at, deleted_by, client_message_id, system_event FROM messages WHERE id = ANY($1::BIGINT[])
 `
 
 func (q *Queries) GetBatchedMessages(ctx context.Context, messageIds []int64) ([]Message, error) {
@@ -109,6 +39,8 @@ func (q *Queries) GetBatchedMessages(ctx context.Context, messageIds []int64) ([
 			&i.SentAt,
 			&i.DeletedAt,
 			&i.DeletedBy,
+			&i.ClientMessageID,
+			&i.SystemEvent,
 		); err != nil {
 			return nil, err
 		}
@@ -121,69 +53,81 @@ func (q *Queries) GetBatchedMessages(ctx context.Context, messageIds []int64) ([
 }
 
 const GetChats = `-- name: GetChats :many
-WITH chat_messages AS (
-    SELECT 
-        COALESCE(m.group_id, CASE WHEN m.sender_id = $1 THEN m.recipient_id ELSE m.sender_id END) AS chat_id, 
-        (m.group_id IS NOT NULL)::BOOLEAN AS is_group_chat, 
-        MAX(m.sent_at) AS last_message_time
-    FROM messages m
-    WHERE (m.sender_id = $1 OR m.recipient_id = $1 OR m.group_id IN (
-        SELECT gm.group_id FROM group_members gm WHERE gm.user_id = $1
-    ))
-    GROUP BY chat_id, is_group_chat
-),
-last_message AS (
-    SELECT 
-        COALESCE(m.group_id, CASE WHEN m.sender_id = $1 THEN m.recipient_id ELSE m.sender_id END) AS chat_id, 
-        m.id AS message_id, 
-        m.sender_id,
-        m.recipient_id,
-        m.group_id,
-        m.text_content,
-        m.media,
-        m.sent_at
-    FROM messages m
-    WHERE (m.sender_id = $1 OR m.recipient_id = $1 OR m.group_id IN (
-        SELECT gm.group_id FROM group_members gm WHERE gm.user_id = $1
-    ))
-),
-unread_count AS (
-    SELECT 
-        COALESCE(m.group_id, CASE WHEN m.sender_id = $1 THEN m.recipient_id ELSE m.sender_id END) AS chat_id, 
-        COUNT(m.id) AS unread_messages_count
-    FROM messages m
-    LEFT JOIN message_read_receipts mrr ON m.id = mrr.message_id AND mrr.user_id = $1
-    WHERE m.sender_id <> $1 
-      AND (m.recipient_id = $1 OR m.group_id IN (
-        SELECT gm.group_id FROM group_members gm WHERE gm.user_id = $1
-      )) 
-      AND mrr.message_id IS NULL
-    GROUP BY chat_id
-)
-SELECT 
+SELECT
     cm.chat_id,
-    cm.is_group_chat,
-    COALESCE(g.name, u.name) AS chat_name,
-    lm.message_id AS last_message_id,
-    COALESCE(uc.unread_messages_count, 0) AS unread_message_count
-FROM chat_messages cm
-JOIN last_message lm ON cm.chat_id = lm.chat_id AND cm.last_message_time = lm.sent_at
-LEFT JOIN groups g ON cm.is_group_chat AND cm.chat_id = g.id
-LEFT JOIN users u ON NOT cm.is_group_chat AND cm.chat_id = u.id
-LEFT JOIN unread_count uc ON cm.chat_id = uc.chat_id
-ORDER BY lm.sent_at DESC
+    COALESCE(c.group_id, cm.peer_user_id)::BIGINT AS target_id,
+    (c.group_id IS NOT NULL)::BOOLEAN AS is_group_chat,
+    COALESCE(g.name, u.name)::TEXT AS chat_name,
+    c.last_message_id,
+    cm.unread_count,
+    d.text_content AS draft_text,
+    d.reply_for_message_id AS draft_reply_for_message_id,
+    d.updated_at AS draft_updated_at,
+    cs.archived_at,
+    cs.pin_order,
+    cs.muted_until
+FROM conversation_members cm
+JOIN conversations c ON c.id = cm.conversation_id
+LEFT JOIN groups g ON g.id = c.group_id
+LEFT JOIN users u ON u.id = cm.peer_user_id
+LEFT JOIN chat_drafts d ON d.user_id = cm.user_id AND d.chat_id = cm.chat_id
+LEFT JOIN chat_settings cs ON cs.user_id = cm.user_id AND cs.chat_id = cm.chat_id
+WHERE cm.user_id = $1
+    AND ($2::BOOLEAN IS NULL OR (cs.archived_at IS NOT NULL) = $2::BOOLEAN)
+    AND ($3::TEXT IS NULL OR cm.chat_id = $3::TEXT)
+    AND ($4::BOOLEAN IS NULL OR (c.group_id IS NOT NULL) = $4::BOOLEAN)
+    AND ($5::TEXT IS NULL OR COALESCE(g.name, u.name) ILIKE '%' || $5::TEXT || '%' ESCAPE '\')
+    AND (NOT $6::BOOLEAN OR cm.unread_count > 0)
+    AND (
+        $7::BIGINT IS NULL
+        OR COALESCE(cs.pin_order, 2147483647) > $8::INT
+        OR (COALESCE(cs.pin_order, 2147483647) = $8::INT AND c.last_message_id < $7::BIGINT)
+    )
+ORDER BY
+    COALESCE(cs.pin_order, 2147483647) ASC,
+    c.last_message_id DESC
+LIMIT $9
 `
 
+type GetChatsParams struct {
+	UserID          int64
+	Archived        *bool
+	ChatID          *string
+	IsGroupChat     *bool
+	Search          *string
+	UnreadOnly      bool
+	CursorMessageID *int64
+	CursorPinOrder  int32
+	ResultLimit     int64
+}
+
 type GetChatsRow struct {
-	ChatID             *int64
-	IsGroupChat        bool
-	ChatName           string
-	LastMessageID      int64
-	UnreadMessageCount int64
+	ChatID                 string
+	TargetID               int64
+	IsGroupChat            bool
+	ChatName               string
+	LastMessageID          int64
+	UnreadCount            int32
+	DraftText              *string
+	DraftReplyForMessageID *int64
+	DraftUpdatedAt         pgtype.Timestamptz
+	ArchivedAt             pgtype.Timestamptz
+	PinOrder               *int32
+	MutedUntil             pgtype.Timestamptz
 }
 
-func (q *Queries) GetChats(ctx context.Context, userID int64) ([]GetChatsRow, error) {
-	rows, err := q.db.Query(ctx, GetChats, userID)
+func (q *Queries) GetChats(ctx context.Context, arg GetChatsParams) ([]GetChatsRow, error) {
+	rows, err := q.db.Query(ctx, GetChats,
+		arg.UserID,
+		arg.Archived,
+		arg.ChatID,
+		arg.IsGroupChat,
+		arg.Search,
+		arg.UnreadOnly,
+		arg.CursorMessageID,
+		arg.CursorPinOrder,
+		arg.ResultLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -193,10 +137,211 @@ func (q *Queries) GetChats(ctx context.Context, userID int64) ([]GetChatsRow, er
 		var i GetChatsRow
 		if err := rows.Scan(
 			&i.ChatID,
+			&i.TargetID,
 			&i.IsGroupChat,
 			&i.ChatName,
 			&i.LastMessageID,
-			&i.UnreadMessageCount,
+			&i.UnreadCount,
+			&i.DraftText,
+			&i.DraftReplyForMessageID,
+			&i.DraftUpdatedAt,
+			&i.ArchivedAt,
+			&i.PinOrder,
+			&i.MutedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const GetConversationChatPreviews = `-- name: GetConversationChatPreviews :many
+SELECT
+    cm.user_id,
+    cm.chat_id,
+    COALESCE(c.group_id, cm.peer_user_id)::BIGINT AS target_id,
+    (c.group_id IS NOT NULL)::BOOLEAN AS is_group_chat,
+    c.last_message_id,
+    cm.unread_count,
+    d.text_content AS draft_text,
+    d.reply_for_message_id AS draft_reply_for_message_id,
+    d.updated_at AS draft_updated_at,
+    cs.archived_at,
+    cs.pin_order,
+    cs.muted_until
+FROM conversation_members cm
+JOIN conversations c ON c.id = cm.conversation_id
+LEFT JOIN chat_drafts d ON d.user_id = cm.user_id AND d.chat_id = cm.chat_id
+LEFT JOIN chat_settings cs ON cs.user_id = cm.user_id AND cs.chat_id = cm.chat_id
+WHERE c.conversation_key = $1
+`
+
+type GetConversationChatPreviewsRow struct {
+	UserID                 int64
+	ChatID                 string
+	TargetID               int64
+	IsGroupChat            bool
+	LastMessageID          int64
+	UnreadCount            int32
+	DraftText              *string
+	DraftReplyForMessageID *int64
+	DraftUpdatedAt         pgtype.Timestamptz
+	ArchivedAt             pgtype.Timestamptz
+	PinOrder               *int32
+	MutedUntil             pgtype.Timestamptz
+}
+
+func (q *Queries) GetConversationChatPreviews(ctx context.Context, conversationKey string) ([]GetConversationChatPreviewsRow, error) {
+	rows, err := q.db.Query(ctx, GetConversationChatPreviews, conversationKey)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetConversationChatPreviewsRow
+	for rows.Next() {
+		var i GetConversationChatPreviewsRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.ChatID,
+			&i.TargetID,
+			&i.IsGroupChat,
+			&i.LastMessageID,
+			&i.UnreadCount,
+			&i.DraftText,
+			&i.DraftReplyForMessageID,
+			&i.DraftUpdatedAt,
+			&i.ArchivedAt,
+			&i.PinOrder,
+			&i.MutedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const GetMessageByClientMessageID = `-- name: GetMessageByClientMessageID :one
+SELECT id, sender_id, recipient_id, group_id, message_type, text_content, media, location, reply_for_message_id, sent_at, deleted_at, deleted_by, client_message_id, system_event FROM messages WHERE sender_id = $1 AND client_message_id = $2
+`
+
+type GetMessageByClientMessageIDParams struct {
+	SenderID        int64
+	ClientMessageID uuid.NullUUID
+}
+
+func (q *Queries) GetMessageByClientMessageID(ctx context.Context, arg GetMessageByClientMessageIDParams) (Message, error) {
+	row := q.db.QueryRow(ctx, GetMessageByClientMessageID, arg.SenderID, arg.ClientMessageID)
+	var i Message
+	err := row.Scan(
+		&i.ID,
+		&i.SenderID,
+		&i.RecipientID,
+		&i.GroupID,
+		&i.MessageType,
+		&i.TextContent,
+		&i.Media,
+		&i.Location,
+		&i.ReplyForMessageID,
+		&i.SentAt,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.ClientMessageID,
+		&i.SystemEvent,
+	)
+	return i, err
+}
+
+const GetMessageByID = `-- name: GetMessageByID :one
+SELECT id, sender_id, recipient_id, group_id, message_type, text_content, media, location, reply_for_message_id, sent_at, deleted_at, deleted_by, client_message_id, system_event FROM messages WHERE id = $1
+`
+
+func (q *Queries) GetMessageByID(ctx context.Context, messageID int64) (Message, error) {
+	row := q.db.QueryRow(ctx, GetMessageByID, messageID)
+	var i Message
+	err := row.Scan(
+		&i.ID,
+		&i.SenderID,
+		&i.RecipientID,
+		&i.GroupID,
+		&i.MessageType,
+		&i.TextContent,
+		&i.Media,
+		&i.Location,
+		&i.ReplyForMessageID,
+		&i.SentAt,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.ClientMessageID,
+		&i.SystemEvent,
+	)
+	return i, err
+}
+
+const GetMessagesAfter = `-- name: GetMessagesAfter :many
+SELECT 
+    m.id, m.sender_id, m.recipient_id, m.group_id, m.message_type, m.text_content, m.media, m.location, m.reply_for_message_id, m.sent_at, m.deleted_at, m.deleted_by, m.client_message_id, m.system_event 
+FROM messages m 
+WHERE
+    CASE 
+        WHEN $1::BIGINT IS NOT NULL THEN
+            (m.sender_id = $2::BIGINT OR m.recipient_id = $2::BIGINT)
+            AND
+            (m.sender_id = $1::BIGINT OR m.recipient_id = $1::BIGINT)
+        WHEN $3::BIGINT IS NOT NULL THEN
+            m.group_id = $3::BIGINT
+    END
+    AND
+    ($4::BIGINT IS NULL OR m.id > $4::BIGINT)
+ORDER BY m.id ASC
+LIMIT $5
+`
+
+type GetMessagesAfterParams struct {
+	TargetUserID  *int64
+	CurrentUserID int64
+	TargetGroupID *int64
+	CursorID      *int64
+	ResultLimit   int64
+}
+
+func (q *Queries) GetMessagesAfter(ctx context.Context, arg GetMessagesAfterParams) ([]Message, error) {
+	rows, err := q.db.Query(ctx, GetMessagesAfter,
+		arg.TargetUserID,
+		arg.CurrentUserID,
+		arg.TargetGroupID,
+		arg.CursorID,
+		arg.ResultLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Message
+	for rows.Next() {
+		var i Message
+		if err := rows.Scan(
+			&i.ID,
+			&i.SenderID,
+			&i.RecipientID,
+			&i.GroupID,
+			&i.MessageType,
+			&i.TextContent,
+			&i.Media,
+			&i.Location,
+			&i.ReplyForMessageID,
+			&i.SentAt,
+			&i.DeletedAt,
+			&i.DeletedBy,
+			&i.ClientMessageID,
+			&i.SystemEvent,
 		); err != nil {
 			return nil, err
 		}
@@ -208,9 +353,9 @@ func (q *Queries) GetChats(ctx context.Context, userID int64) ([]GetChatsRow, er
 	return items, nil
 }
 
-const GetMessages = `-- name: GetMessages :many
+const GetMessagesBefore = `-- name: GetMessagesBefore :many
 SELECT 
-    m.id, m.sender_id, m.recipient_id, m.group_id, m.message_type, m.text_content, m.media, m.location, m.reply_for_message_id, m.sent_at, m.deleted_at, m.deleted_by 
+    m.id, m.sender_id, m.recipient_id, m.group_id, m.message_type, m.text_content, m.media, m.location, m.reply_for_message_id, m.sent_at, m.deleted_at, m.deleted_by, m.client_message_id, m.system_event 
 FROM messages m 
 WHERE
     CASE 
@@ -227,7 +372,7 @@ ORDER BY m.id DESC
 LIMIT $5
 `
 
-type GetMessagesParams struct {
+type GetMessagesBeforeParams struct {
 	TargetUserID  *int64
 	CurrentUserID int64
 	TargetGroupID *int64
@@ -235,8 +380,8 @@ type GetMessagesParams struct {
 	ResultLimit   int64
 }
 
-func (q *Queries) GetMessages(ctx context.Context, arg GetMessagesParams) ([]Message, error) {
-	rows, err := q.db.Query(ctx, GetMessages,
+func (q *Queries) GetMessagesBefore(ctx context.Context, arg GetMessagesBeforeParams) ([]Message, error) {
+	rows, err := q.db.Query(ctx, GetMessagesBefore,
 		arg.TargetUserID,
 		arg.CurrentUserID,
 		arg.TargetGroupID,
@@ -263,6 +408,8 @@ func (q *Queries) GetMessages(ctx context.Context, arg GetMessagesParams) ([]Mes
 			&i.SentAt,
 			&i.DeletedAt,
 			&i.DeletedBy,
+			&i.ClientMessageID,
+			&i.SystemEvent,
 		); err != nil {
 			return nil, err
 		}
@@ -274,6 +421,49 @@ func (q *Queries) GetMessages(ctx context.Context, arg GetMessagesParams) ([]Mes
 	return items, nil
 }
 
+const InsertChatReadReceipts = `-- name: InsertChatReadReceipts :execrows
+INSERT INTO message_read_receipts (
+    message_id,
+    user_id,
+    read_at
+)
+SELECT
+    m.id,
+    $1::BIGINT,
+    NOW()
+FROM messages m
+WHERE
+    CASE
+        WHEN $2::BIGINT IS NOT NULL THEN
+            m.sender_id = $2::BIGINT AND m.recipient_id = $1::BIGINT
+        WHEN $3::BIGINT IS NOT NULL THEN
+            m.group_id = $3::BIGINT AND m.sender_id <> $1::BIGINT
+    END
+    AND
+    ($4::BIGINT IS NULL OR m.id > $4::BIGINT)
+ON CONFLICT (message_id, user_id) DO NOTHING
+`
+
+type InsertChatReadReceiptsParams struct {
+	UserID         int64
+	TargetUserID   *int64
+	TargetGroupID  *int64
+	AfterMessageID *int64
+}
+
+func (q *Queries) InsertChatReadReceipts(ctx context.Context, arg InsertChatReadReceiptsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, InsertChatReadReceipts,
+		arg.UserID,
+		arg.TargetUserID,
+		arg.TargetGroupID,
+		arg.AfterMessageID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const InsertMessage = `-- name: InsertMessage :one
 INSERT INTO messages (
     sender_id,
@@ -283,7 +473,9 @@ INSERT INTO messages (
     text_content,
     media,
     location,
-    reply_for_message_id
+    reply_for_message_id,
+    client_message_id,
+    system_event
 ) VALUES (
     $1,
     $2,
@@ -292,8 +484,11 @@ INSERT INTO messages (
     $5,
     $6,
     $7,
-    $8
-) RETURNING id, sender_id, recipient_id, group_id, message_type, text_content, media, location, reply_for_message_id, sent_at, deleted_at, deleted_by
+    $8,
+    $9,
+    $10
+) ON CONFLICT (sender_id, client_message_id) DO NOTHING 
+RETURNING id, sender_id, recipient_id, group_id, message_type, text_content, media, location, reply_for_message_id, sent_at, deleted_at, deleted_by, client_message_id, system_event
 `
 
 type InsertMessageParams struct {
@@ -305,6 +500,8 @@ type InsertMessageParams struct {
 	Media             *string
 	Location          types.Point
 	ReplyForMessageID *int64
+	ClientMessageID   uuid.NullUUID
+	SystemEvent       []byte
 }
 
 func (q *Queries) InsertMessage(ctx context.Context, arg InsertMessageParams) (Message, error) {
@@ -317,6 +514,8 @@ func (q *Queries) InsertMessage(ctx context.Context, arg InsertMessageParams) (M
 		arg.Media,
 		arg.Location,
 		arg.ReplyForMessageID,
+		arg.ClientMessageID,
+		arg.SystemEvent,
 	)
 	var i Message
 	err := row.Scan(
@@ -332,6 +531,8 @@ func (q *Queries) InsertMessage(ctx context.Context, arg InsertMessageParams) (M
 		&i.SentAt,
 		&i.DeletedAt,
 		&i.DeletedBy,
+		&i.ClientMessageID,
+		&i.SystemEvent,
 	)
 	return i, err
 }
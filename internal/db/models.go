@@ -10,6 +10,82 @@ import (
 	"github.com/thanishsid/dingilink-server/internal/types"
 )
 
+type Channel struct {
+	ID          int64
+	Handle      *string
+	Name        string
+	Description *string
+	Image       *string
+	IsPublic    bool
+	InviteCode  string
+	CreatedBy   int64
+	CreatedAt   pgtype.Timestamptz
+}
+
+type ChannelMember struct {
+	ID        int64
+	ChannelID int64
+	UserID    int64
+	Role      string
+	JoinedAt  pgtype.Timestamptz
+}
+
+type ChannelPost struct {
+	ID          int64
+	ChannelID   int64
+	AuthorID    int64
+	TextContent *string
+	Media       *string
+	PostedAt    pgtype.Timestamptz
+	DeletedAt   pgtype.Timestamptz
+}
+
+type ChannelPostReaction struct {
+	ID        int64
+	PostID    int64
+	UserID    int64
+	Emoji     string
+	ReactedAt pgtype.Timestamptz
+}
+
+type ChatDraft struct {
+	ID                int64
+	UserID            int64
+	ChatID            string
+	TextContent       string
+	ReplyForMessageID *int64
+	UpdatedAt         pgtype.Timestamptz
+}
+
+type ChatSetting struct {
+	ID         int64
+	UserID     int64
+	ChatID     string
+	ArchivedAt pgtype.Timestamptz
+	PinOrder   *int32
+	MutedUntil pgtype.Timestamptz
+	UpdatedAt  pgtype.Timestamptz
+}
+
+type Conversation struct {
+	ID              int64
+	ConversationKey string
+	GroupID         *int64
+	LastMessageID   int64
+	LastActivityAt  pgtype.Timestamptz
+	EventSequence   int64
+}
+
+type ConversationMember struct {
+	ID                int64
+	ConversationID    int64
+	UserID            int64
+	ChatID            string
+	PeerUserID        *int64
+	UnreadCount       int32
+	LastReadMessageID *int64
+}
+
 type EmailVerificationToken struct {
 	ID        int64
 	UserID    int64
@@ -28,12 +104,46 @@ type Friendship struct {
 }
 
 type Group struct {
-	ID          int64
-	Name        string
-	Image       *string
-	Description *string
-	CreatedBy   int64
-	CreatedAt   pgtype.Timestamptz
+	ID                        int64
+	Name                      string
+	Image                     *string
+	Description               *string
+	CreatedBy                 int64
+	CreatedAt                 pgtype.Timestamptz
+	JoinPolicy                string
+	OnlyAdminsCanSendMessages bool
+	OnlyAdminsCanEditInfo     bool
+	OnlyAdminsCanAddMembers   bool
+}
+
+type GroupInvite struct {
+	ID        int64
+	GroupID   int64
+	Code      string
+	CreatedBy int64
+	MaxUses   *int32
+	UseCount  int32
+	ExpiresAt pgtype.Timestamptz
+	RevokedAt pgtype.Timestamptz
+	CreatedAt pgtype.Timestamptz
+}
+
+type GroupInviteUse struct {
+	ID       int64
+	InviteID int64
+	UserID   int64
+	UsedAt   pgtype.Timestamptz
+}
+
+type GroupJoinRequest struct {
+	ID        int64
+	GroupID   int64
+	UserID    int64
+	InviteID  *int64
+	Status    string
+	CreatedAt pgtype.Timestamptz
+	DecidedBy *int64
+	DecidedAt pgtype.Timestamptz
 }
 
 type GroupMember struct {
@@ -57,6 +167,16 @@ type Message struct {
 	SentAt            pgtype.Timestamptz
 	DeletedAt         pgtype.Timestamptz
 	DeletedBy         *int64
+	ClientMessageID   uuid.NullUUID
+	SystemEvent       []byte
+}
+
+type MessageEvent struct {
+	ID        int64
+	UserID    int64
+	ChatID    string
+	Payload   []byte
+	CreatedAt pgtype.Timestamptz
 }
 
 type MessageReaction struct {
@@ -81,6 +201,30 @@ type Permission struct {
 	SortIndex   int64
 }
 
+type Poll struct {
+	ID             int64
+	MessageID      int64
+	Question       string
+	MultipleChoice bool
+	Anonymous      bool
+	ClosesAt       pgtype.Timestamptz
+}
+
+type PollOption struct {
+	ID         int64
+	PollID     int64
+	OptionText string
+	SortIndex  int32
+}
+
+type PollVote struct {
+	ID           int64
+	PollID       int64
+	PollOptionID int64
+	UserID       int64
+	VotedAt      pgtype.Timestamptz
+}
+
 type Post struct {
 	ID        int64
 	Caption   *string
@@ -159,6 +303,13 @@ type UserHierarchy struct {
 	ChildID  int64
 }
 
+type UserLocation struct {
+	UserID    int64
+	Location  types.Point
+	Visible   bool
+	UpdatedAt pgtype.Timestamptz
+}
+
 type UserRole struct {
 	ID     int64
 	UserID int64
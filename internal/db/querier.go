@@ -8,45 +8,150 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
+	AcceptFriendRequest(ctx context.Context, arg AcceptFriendRequestParams) (Friendship, error)
+	BackfillConversationUnreadCounts(ctx context.Context) (int64, error)
+	BackfillDirectConversationMembers(ctx context.Context) (int64, error)
+	BackfillDirectConversations(ctx context.Context) (int64, error)
+	BackfillGroupConversationMembers(ctx context.Context) (int64, error)
+	BackfillGroupConversations(ctx context.Context) (int64, error)
+	CheckChannelHandleExists(ctx context.Context, handle string) (bool, error)
 	CheckEmailExists(ctx context.Context, email string) (bool, error)
-	CheckMessagesHasNextPage(ctx context.Context, arg CheckMessagesHasNextPageParams) (bool, error)
-	CheckMessagesHasPreviousPage(ctx context.Context, arg CheckMessagesHasPreviousPageParams) (bool, error)
+	CheckGroupMember(ctx context.Context, arg CheckGroupMemberParams) (bool, error)
 	CheckUsernameExists(ctx context.Context, username string) (bool, error)
+	CheckUsersBlocked(ctx context.Context, arg CheckUsersBlockedParams) (bool, error)
+	DecideGroupJoinRequest(ctx context.Context, arg DecideGroupJoinRequestParams) (GroupJoinRequest, error)
+	DeleteChannel(ctx context.Context, channelID int64) error
+	DeleteChannelMember(ctx context.Context, arg DeleteChannelMemberParams) (int64, error)
+	DeleteChannelPost(ctx context.Context, postID int64) (int64, error)
+	DeleteChannelPostReaction(ctx context.Context, arg DeleteChannelPostReactionParams) (int64, error)
+	DeleteChatDraft(ctx context.Context, arg DeleteChatDraftParams) (int64, error)
+	DeleteChatDraftsByChatID(ctx context.Context, chatID string) error
+	DeleteChatSettingsByChatID(ctx context.Context, chatID string) error
+	DeleteConversationMember(ctx context.Context, arg DeleteConversationMemberParams) error
 	DeleteEmailVerificationToken(ctx context.Context, tokenID int64) error
+	DeleteFriendRequest(ctx context.Context, arg DeleteFriendRequestParams) (int64, error)
+	DeleteFriendship(ctx context.Context, arg DeleteFriendshipParams) (int64, error)
+	DeleteFriendshipsBetween(ctx context.Context, arg DeleteFriendshipsBetweenParams) ([]Friendship, error)
+	DeleteGroup(ctx context.Context, groupID int64) error
+	DeleteGroupMember(ctx context.Context, arg DeleteGroupMemberParams) (int64, error)
+	DeleteMessageEventsBefore(ctx context.Context, before pgtype.Timestamptz) (int64, error)
 	DeletePermission(ctx context.Context, name string) error
+	DeletePollVotes(ctx context.Context, arg DeletePollVotesParams) (int64, error)
 	DeleteRefreshToken(ctx context.Context, tokenID uuid.UUID) error
 	DeleteRefreshTokensByUserID(ctx context.Context, userID int64) error
 	DeleteRole(ctx context.Context, name string) error
 	DeleteRolePermissions(ctx context.Context, roleName string) error
+	DeleteUserBlock(ctx context.Context, arg DeleteUserBlockParams) (int64, error)
+	GetBatchedChannelMemberCounts(ctx context.Context, channelIds []int64) ([]GetBatchedChannelMemberCountsRow, error)
+	GetBatchedChannelPostReactionCounts(ctx context.Context, postIds []int64) ([]GetBatchedChannelPostReactionCountsRow, error)
+	GetBatchedGroupInviteUses(ctx context.Context, inviteIds []int64) ([]GroupInviteUse, error)
+	GetBatchedGroupMemberCounts(ctx context.Context, groupIds []int64) ([]GetBatchedGroupMemberCountsRow, error)
 	GetBatchedGroupMembers(ctx context.Context, groupIds []int64) ([]GetBatchedGroupMembersRow, error)
+	GetBatchedGroupMemberships(ctx context.Context, arg GetBatchedGroupMembershipsParams) ([]GetBatchedGroupMembershipsRow, error)
 	GetBatchedGroups(ctx context.Context, groupIds []int64) ([]Group, error)
 	GetBatchedMessages(ctx context.Context, messageIds []int64) ([]Message, error)
+	GetBatchedPollOptions(ctx context.Context, pollIds []int64) ([]GetBatchedPollOptionsRow, error)
+	GetBatchedPolls(ctx context.Context, messageIds []int64) ([]Poll, error)
 	GetBatchedUsers(ctx context.Context, userIds []int64) ([]GetBatchedUsersRow, error)
-	GetChats(ctx context.Context, userID int64) ([]GetChatsRow, error)
+	GetBatchedUsersBlocked(ctx context.Context, arg GetBatchedUsersBlockedParams) ([]GetBatchedUsersBlockedRow, error)
+	GetBlockedUsersPage(ctx context.Context, arg GetBlockedUsersPageParams) ([]Friendship, error)
+	GetChannelByHandle(ctx context.Context, handle string) (Channel, error)
+	GetChannelByID(ctx context.Context, channelID int64) (Channel, error)
+	GetChannelByInviteCode(ctx context.Context, inviteCode string) (Channel, error)
+	GetChannelMember(ctx context.Context, arg GetChannelMemberParams) (ChannelMember, error)
+	GetChannelPostByID(ctx context.Context, postID int64) (ChannelPost, error)
+	GetChannelPosts(ctx context.Context, arg GetChannelPostsParams) ([]ChannelPost, error)
+	GetChats(ctx context.Context, arg GetChatsParams) ([]GetChatsRow, error)
+	GetConversationChatPreviews(ctx context.Context, conversationKey string) ([]GetConversationChatPreviewsRow, error)
+	GetConversationMember(ctx context.Context, arg GetConversationMemberParams) (ConversationMember, error)
 	GetEmailVerificationToken(ctx context.Context, token string) (GetEmailVerificationTokenRow, error)
+	GetFriendsPage(ctx context.Context, arg GetFriendsPageParams) ([]Friendship, error)
+	GetFriendshipBetween(ctx context.Context, arg GetFriendshipBetweenParams) (Friendship, error)
 	GetGroupByID(ctx context.Context, groupID int64) (Group, error)
+	GetGroupInviteByCode(ctx context.Context, code string) (GroupInvite, error)
+	GetGroupInviteByID(ctx context.Context, inviteID int64) (GroupInvite, error)
+	GetGroupInvites(ctx context.Context, groupID int64) ([]GroupInvite, error)
+	GetGroupJoinRequestByID(ctx context.Context, requestID int64) (GroupJoinRequest, error)
+	GetGroupMember(ctx context.Context, arg GetGroupMemberParams) (GroupMember, error)
+	GetGroupMemberCount(ctx context.Context, groupID int64) (int64, error)
 	GetGroupMembers(ctx context.Context, groupID int64) ([]GroupMember, error)
-	GetMessages(ctx context.Context, arg GetMessagesParams) ([]Message, error)
+	GetGroupMembersPage(ctx context.Context, arg GetGroupMembersPageParams) ([]GetGroupMembersPageRow, error)
+	GetIncomingFriendRequestsPage(ctx context.Context, arg GetIncomingFriendRequestsPageParams) ([]Friendship, error)
+	GetMessageByClientMessageID(ctx context.Context, arg GetMessageByClientMessageIDParams) (Message, error)
+	GetMessageByID(ctx context.Context, messageID int64) (Message, error)
+	GetMessageEventsAfter(ctx context.Context, arg GetMessageEventsAfterParams) ([]MessageEvent, error)
+	GetMessagesAfter(ctx context.Context, arg GetMessagesAfterParams) ([]Message, error)
+	GetMessagesBefore(ctx context.Context, arg GetMessagesBeforeParams) ([]Message, error)
+	GetNearbyUsers(ctx context.Context, arg GetNearbyUsersParams) ([]GetNearbyUsersRow, error)
+	GetOutgoingFriendRequestsPage(ctx context.Context, arg GetOutgoingFriendRequestsPageParams) ([]Friendship, error)
+	GetPendingGroupJoinRequest(ctx context.Context, arg GetPendingGroupJoinRequestParams) (GroupJoinRequest, error)
+	GetPendingGroupJoinRequests(ctx context.Context, groupID int64) ([]GroupJoinRequest, error)
 	GetPermissions(ctx context.Context) ([]Permission, error)
+	GetPollByMessageID(ctx context.Context, messageID int64) (Poll, error)
 	GetRefreshToken(ctx context.Context, tokenID uuid.UUID) (RefreshToken, error)
 	GetRoles(ctx context.Context) ([]Role, error)
 	GetUser(ctx context.Context, userID int64) (GetUserRow, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
+	GetUserChannels(ctx context.Context, userID int64) ([]Channel, error)
+	GetUserGroups(ctx context.Context, userID int64) ([]Group, error)
+	GetUserLocation(ctx context.Context, userID int64) (UserLocation, error)
 	GetUserRoles(ctx context.Context, userID int64) ([]string, error)
+	IncrementConversationEventSequence(ctx context.Context, conversationKey string) (int64, error)
+	IncrementConversationUnreadCounts(ctx context.Context, arg IncrementConversationUnreadCountsParams) error
+	InsertChannel(ctx context.Context, arg InsertChannelParams) (Channel, error)
+	InsertChannelMember(ctx context.Context, arg InsertChannelMemberParams) (int64, error)
+	InsertChannelPost(ctx context.Context, arg InsertChannelPostParams) (ChannelPost, error)
+	InsertChatReadReceipts(ctx context.Context, arg InsertChatReadReceiptsParams) (int64, error)
 	InsertEmailVerificationToken(ctx context.Context, arg InsertEmailVerificationTokenParams) (EmailVerificationToken, error)
+	InsertFriendRequest(ctx context.Context, arg InsertFriendRequestParams) (Friendship, error)
+	InsertGroup(ctx context.Context, arg InsertGroupParams) (Group, error)
+	InsertGroupConversationMember(ctx context.Context, arg InsertGroupConversationMemberParams) error
+	InsertGroupConversationMembers(ctx context.Context, arg InsertGroupConversationMembersParams) error
+	InsertGroupInvite(ctx context.Context, arg InsertGroupInviteParams) (GroupInvite, error)
+	InsertGroupInviteUse(ctx context.Context, arg InsertGroupInviteUseParams) error
+	InsertGroupJoinRequest(ctx context.Context, arg InsertGroupJoinRequestParams) (GroupJoinRequest, error)
+	InsertGroupMember(ctx context.Context, arg InsertGroupMemberParams) (int64, error)
 	InsertMessage(ctx context.Context, arg InsertMessageParams) (Message, error)
+	InsertMessageEvents(ctx context.Context, arg InsertMessageEventsParams) ([]InsertMessageEventsRow, error)
+	InsertPoll(ctx context.Context, arg InsertPollParams) (Poll, error)
+	InsertPollOption(ctx context.Context, arg InsertPollOptionParams) error
+	InsertPollVote(ctx context.Context, arg InsertPollVoteParams) error
 	InsertRefreshToken(ctx context.Context, arg InsertRefreshTokenParams) error
 	InsertRolePermission(ctx context.Context, arg InsertRolePermissionParams) error
 	InsertUser(ctx context.Context, arg InsertUserParams) (User, error)
+	InsertUserBlock(ctx context.Context, arg InsertUserBlockParams) (int64, error)
 	InsertUserRole(ctx context.Context, arg InsertUserRoleParams) error
+	LockPoll(ctx context.Context, id int64) error
+	MarkConversationAsRead(ctx context.Context, arg MarkConversationAsReadParams) (int64, error)
+	PinChat(ctx context.Context, arg PinChatParams) (ChatSetting, error)
+	RevokeGroupInvite(ctx context.Context, inviteID int64) (int64, error)
+	SearchPublicChannels(ctx context.Context, arg SearchPublicChannelsParams) ([]Channel, error)
+	SearchUserIDs(ctx context.Context, arg SearchUserIDsParams) ([]int64, error)
+	SetChannelMemberRole(ctx context.Context, arg SetChannelMemberRoleParams) (int64, error)
+	SetChatArchived(ctx context.Context, arg SetChatArchivedParams) (ChatSetting, error)
+	SetChatMutedUntil(ctx context.Context, arg SetChatMutedUntilParams) (ChatSetting, error)
+	SetGroupMemberAdmin(ctx context.Context, arg SetGroupMemberAdminParams) (int64, error)
+	UnpinChat(ctx context.Context, arg UnpinChatParams) (ChatSetting, error)
+	UpdateChannel(ctx context.Context, arg UpdateChannelParams) (Channel, error)
+	UpdateChannelInviteCode(ctx context.Context, arg UpdateChannelInviteCodeParams) (Channel, error)
+	UpdateGroup(ctx context.Context, arg UpdateGroupParams) (Group, error)
+	UpdateGroupOwner(ctx context.Context, arg UpdateGroupOwnerParams) error
+	UpdateGroupPolicies(ctx context.Context, arg UpdateGroupPoliciesParams) (Group, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) error
 	UpdateUserEmailVerifiedAt(ctx context.Context, arg UpdateUserEmailVerifiedAtParams) error
 	UpdateUserOnlineStatus(ctx context.Context, arg UpdateUserOnlineStatusParams) error
+	UpsertChannelPostReaction(ctx context.Context, arg UpsertChannelPostReactionParams) error
+	UpsertChatDraft(ctx context.Context, arg UpsertChatDraftParams) (ChatDraft, error)
+	UpsertConversation(ctx context.Context, arg UpsertConversationParams) (Conversation, error)
+	UpsertConversationMember(ctx context.Context, arg UpsertConversationMemberParams) error
 	UpsertPermission(ctx context.Context, arg UpsertPermissionParams) error
 	UpsertRole(ctx context.Context, arg UpsertRoleParams) error
+	UpsertUserLocation(ctx context.Context, arg UpsertUserLocationParams) error
+	UseGroupInvite(ctx context.Context, inviteID int64) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,149 @@
+-- name: InsertChannel :one
+INSERT INTO channels (
+    handle,
+    name,
+    description,
+    image,
+    is_public,
+    invite_code,
+    created_by
+) VALUES (
+    @handle,
+    @name,
+    @description,
+    @image,
+    @is_public,
+    @invite_code,
+    @created_by
+) RETURNING *;
+
+
+-- name: GetChannelByID :one
+SELECT * FROM channels WHERE id = @channel_id;
+
+
+-- name: GetChannelByHandle :one
+SELECT * FROM channels WHERE handle = @handle::TEXT;
+
+
+-- name: GetChannelByInviteCode :one
+SELECT * FROM channels WHERE invite_code = @invite_code;
+
+
+-- name: SearchPublicChannels :many
+SELECT * FROM channels
+WHERE is_public
+    AND (handle ILIKE @search::TEXT || '%' ESCAPE '\' OR name ILIKE '%' || @search::TEXT || '%' ESCAPE '\')
+ORDER BY name ASC
+LIMIT @max_results;
+
+
+-- name: GetUserChannels :many
+SELECT c.*
+FROM channels c
+JOIN channel_members cm ON cm.channel_id = c.id
+WHERE cm.user_id = @user_id
+ORDER BY c.name ASC;
+
+
+-- name: UpdateChannel :one
+UPDATE channels SET
+    handle = @handle,
+    name = @name,
+    description = @description,
+    image = @image,
+    is_public = @is_public
+WHERE id = @channel_id
+RETURNING *;
+
+
+-- name: UpdateChannelInviteCode :one
+UPDATE channels SET invite_code = @invite_code WHERE id = @channel_id RETURNING *;
+
+
+-- name: DeleteChannel :exec
+DELETE FROM channels WHERE id = @channel_id;
+
+
+-- name: InsertChannelMember :execrows
+INSERT INTO channel_members (channel_id, user_id, role)
+VALUES (@channel_id, @user_id, @role)
+ON CONFLICT (channel_id, user_id) DO NOTHING;
+
+
+-- name: GetChannelMember :one
+SELECT * FROM channel_members WHERE channel_id = @channel_id AND user_id = @user_id;
+
+
+-- name: DeleteChannelMember :execrows
+DELETE FROM channel_members WHERE channel_id = @channel_id AND user_id = @user_id;
+
+
+-- name: SetChannelMemberRole :execrows
+UPDATE channel_members SET role = @role
+WHERE channel_id = @channel_id AND user_id = @user_id AND role <> 'owner';
+
+
+-- name: GetBatchedChannelMemberCounts :many
+SELECT
+    channel_id,
+    COUNT(*) AS member_count
+FROM channel_members
+WHERE channel_id = ANY(@channel_ids::BIGINT[])
+GROUP BY channel_id;
+
+
+-- name: InsertChannelPost :one
+INSERT INTO channel_posts (
+    channel_id,
+    author_id,
+    text_content,
+    media
+) VALUES (
+    @channel_id,
+    @author_id,
+    @text_content,
+    @media
+) RETURNING *;
+
+
+-- name: GetChannelPostByID :one
+SELECT * FROM channel_posts WHERE id = @post_id AND deleted_at IS NULL;
+
+
+-- name: GetChannelPosts :many
+SELECT * FROM channel_posts
+WHERE channel_id = @channel_id
+    AND deleted_at IS NULL
+    AND (sqlc.narg('cursor')::BIGINT IS NULL OR id < sqlc.narg('cursor')::BIGINT)
+ORDER BY id DESC
+LIMIT @result_limit;
+
+
+-- name: DeleteChannelPost :execrows
+UPDATE channel_posts SET deleted_at = NOW() WHERE id = @post_id AND deleted_at IS NULL;
+
+
+-- name: UpsertChannelPostReaction :exec
+INSERT INTO channel_post_reactions (post_id, user_id, emoji)
+VALUES (@post_id, @user_id, @emoji)
+ON CONFLICT (post_id, user_id) DO UPDATE SET emoji = EXCLUDED.emoji, reacted_at = NOW();
+
+
+-- name: DeleteChannelPostReaction :execrows
+DELETE FROM channel_post_reactions WHERE post_id = @post_id AND user_id = @user_id;
+
+
+-- name: GetBatchedChannelPostReactionCounts :many
+SELECT
+    post_id,
+    emoji,
+    COUNT(*) AS reaction_count
+FROM channel_post_reactions
+WHERE post_id = ANY(@post_ids::BIGINT[])
+GROUP BY post_id, emoji
+ORDER BY post_id, reaction_count DESC;
+
+
+-- name: CheckChannelHandleExists :one
+SELECT EXISTS(SELECT 1 FROM channels WHERE handle = @handle::TEXT);
\ No newline at end of file
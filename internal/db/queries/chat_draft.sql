@@ -0,0 +1,26 @@
+-- name: UpsertChatDraft :one
+INSERT INTO chat_drafts (
+    user_id,
+    chat_id,
+    text_content,
+    reply_for_message_id
+) VALUES (
+    @user_id,
+    @chat_id,
+    @text_content,
+    @reply_for_message_id
+)
+ON CONFLICT (user_id, chat_id) DO UPDATE
+SET
+    text_content = EXCLUDED.text_content,
+    reply_for_message_id = EXCLUDED.reply_for_message_id,
+    updated_at = NOW()
+RETURNING *;
+
+
+-- name: DeleteChatDraft :execrows
+DELETE FROM chat_drafts WHERE user_id = @user_id AND chat_id = @chat_id;
+
+
+-- name: DeleteChatDraftsByChatID :exec
+DELETE FROM chat_drafts WHERE chat_id = @chat_id;
\ No newline at end of file
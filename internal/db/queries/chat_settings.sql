@@ -0,0 +1,73 @@
+-- name: SetChatArchived :one
+INSERT INTO chat_settings (
+    user_id,
+    chat_id,
+    archived_at
+) VALUES (
+    @user_id,
+    @chat_id,
+    CASE WHEN @archived::BOOLEAN THEN NOW() ELSE NULL END
+)
+ON CONFLICT (user_id, chat_id) DO UPDATE
+SET
+    archived_at = EXCLUDED.archived_at,
+    -- Archiving a chat unpins it
+    pin_order = CASE WHEN @archived::BOOLEAN THEN NULL ELSE chat_settings.pin_order END,
+    updated_at = NOW()
+RETURNING *;
+
+
+-- name: PinChat :one
+INSERT INTO chat_settings (
+    user_id,
+    chat_id,
+    pin_order
+) VALUES (
+    @user_id,
+    @chat_id,
+    (SELECT COALESCE(MAX(cs.pin_order), 0) + 1 FROM chat_settings cs WHERE cs.user_id = @user_id)
+)
+ON CONFLICT (user_id, chat_id) DO UPDATE
+SET
+    -- Keep the position of an already pinned chat
+    pin_order = COALESCE(chat_settings.pin_order, EXCLUDED.pin_order),
+    -- Pinning a chat unarchives it
+    archived_at = NULL,
+    updated_at = NOW()
+RETURNING *;
+
+
+-- name: UnpinChat :one
+INSERT INTO chat_settings (
+    user_id,
+    chat_id
+) VALUES (
+    @user_id,
+    @chat_id
+)
+ON CONFLICT (user_id, chat_id) DO UPDATE
+SET
+    pin_order = NULL,
+    updated_at = NOW()
+RETURNING *;
+
+
+-- name: SetChatMutedUntil :one
+INSERT INTO chat_settings (
+    user_id,
+    chat_id,
+    muted_until
+) VALUES (
+    @user_id,
+    @chat_id,
+    @muted_until
+)
+ON CONFLICT (user_id, chat_id) DO UPDATE
+SET
+    muted_until = EXCLUDED.muted_until,
+    updated_at = NOW()
+RETURNING *;
+
+
+-- name: DeleteChatSettingsByChatID :exec
+DELETE FROM chat_settings WHERE chat_id = @chat_id;
\ No newline at end of file
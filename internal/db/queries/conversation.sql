@@ -0,0 +1,186 @@
+-- name: UpsertConversation :one
+INSERT INTO conversations (
+    conversation_key,
+    group_id,
+    last_message_id,
+    last_activity_at,
+    event_sequence
+) VALUES (
+    @conversation_key,
+    @group_id,
+    @last_message_id,
+    @last_activity_at,
+    1
+)
+ON CONFLICT (conversation_key) DO UPDATE
+SET
+    last_message_id = GREATEST(conversations.last_message_id, EXCLUDED.last_message_id),
+    last_activity_at = GREATEST(conversations.last_activity_at, EXCLUDED.last_activity_at),
+    event_sequence = conversations.event_sequence + 1
+RETURNING *;
+
+
+-- name: IncrementConversationEventSequence :one
+UPDATE conversations
+SET event_sequence = event_sequence + 1
+WHERE conversation_key = @conversation_key
+RETURNING event_sequence;
+
+
+-- name: UpsertConversationMember :exec
+INSERT INTO conversation_members (
+    conversation_id,
+    user_id,
+    chat_id,
+    peer_user_id
+) VALUES (
+    @conversation_id,
+    @user_id,
+    @chat_id,
+    @peer_user_id
+)
+ON CONFLICT (conversation_id, user_id) DO NOTHING;
+
+
+-- name: InsertGroupConversationMembers :exec
+INSERT INTO conversation_members (
+    conversation_id,
+    user_id,
+    chat_id
+)
+SELECT
+    @conversation_id::BIGINT,
+    gm.user_id,
+    'group_' || gm.group_id
+FROM group_members gm
+WHERE gm.group_id = @group_id
+ON CONFLICT (conversation_id, user_id) DO NOTHING;
+
+
+-- name: IncrementConversationUnreadCounts :exec
+UPDATE conversation_members
+SET unread_count = unread_count + 1
+WHERE conversation_id = @conversation_id AND user_id <> @sender_id;
+
+
+-- name: GetConversationMember :one
+SELECT * FROM conversation_members WHERE user_id = @user_id AND chat_id = @chat_id;
+
+
+-- name: MarkConversationAsRead :execrows
+UPDATE conversation_members cm
+SET
+    unread_count = 0,
+    last_read_message_id = c.last_message_id
+FROM conversations c
+WHERE c.id = cm.conversation_id AND cm.user_id = @user_id AND cm.chat_id = @chat_id;
+
+
+-- name: BackfillGroupConversations :execrows
+INSERT INTO conversations (
+    conversation_key,
+    group_id,
+    last_message_id,
+    last_activity_at
+)
+SELECT DISTINCT ON (m.group_id)
+    'group_' || m.group_id,
+    m.group_id,
+    m.id,
+    COALESCE(m.sent_at, NOW())
+FROM messages m
+WHERE m.group_id IS NOT NULL
+ORDER BY m.group_id, m.id DESC
+ON CONFLICT (conversation_key) DO UPDATE
+SET
+    last_message_id = EXCLUDED.last_message_id,
+    last_activity_at = EXCLUDED.last_activity_at;
+
+
+-- name: BackfillDirectConversations :execrows
+INSERT INTO conversations (
+    conversation_key,
+    last_message_id,
+    last_activity_at
+)
+SELECT DISTINCT ON (LEAST(m.sender_id, m.recipient_id), GREATEST(m.sender_id, m.recipient_id))
+    'direct_' || LEAST(m.sender_id, m.recipient_id) || '_' || GREATEST(m.sender_id, m.recipient_id),
+    m.id,
+    COALESCE(m.sent_at, NOW())
+FROM messages m
+WHERE m.recipient_id IS NOT NULL
+ORDER BY LEAST(m.sender_id, m.recipient_id), GREATEST(m.sender_id, m.recipient_id), m.id DESC
+ON CONFLICT (conversation_key) DO UPDATE
+SET
+    last_message_id = EXCLUDED.last_message_id,
+    last_activity_at = EXCLUDED.last_activity_at;
+
+
+-- name: BackfillGroupConversationMembers :execrows
+INSERT INTO conversation_members (
+    conversation_id,
+    user_id,
+    chat_id
+)
+SELECT
+    c.id,
+    gm.user_id,
+    'group_' || c.group_id
+FROM conversations c
+JOIN group_members gm ON gm.group_id = c.group_id
+ON CONFLICT (conversation_id, user_id) DO NOTHING;
+
+
+-- name: BackfillDirectConversationMembers :execrows
+INSERT INTO conversation_members (
+    conversation_id,
+    user_id,
+    chat_id,
+    peer_user_id
+)
+SELECT c.id, m.sender_id, 'direct_' || m.recipient_id, m.recipient_id
+FROM conversations c
+JOIN messages m ON m.id = c.last_message_id
+WHERE c.group_id IS NULL
+UNION
+SELECT c.id, m.recipient_id, 'direct_' || m.sender_id, m.sender_id
+FROM conversations c
+JOIN messages m ON m.id = c.last_message_id
+WHERE c.group_id IS NULL
+ON CONFLICT (conversation_id, user_id) DO NOTHING;
+
+
+-- name: BackfillConversationUnreadCounts :execrows
+UPDATE conversation_members cm
+SET unread_count = (
+    SELECT COUNT(m.id)
+    FROM messages m
+    LEFT JOIN message_read_receipts mrr ON mrr.message_id = m.id AND mrr.user_id = cm.user_id
+    WHERE m.sender_id <> cm.user_id
+      AND mrr.message_id IS NULL
+      AND CASE
+          WHEN cm.peer_user_id IS NOT NULL THEN m.sender_id = cm.peer_user_id AND m.recipient_id = cm.user_id
+          ELSE m.group_id = c.group_id
+      END
+)
+FROM conversations c
+WHERE c.id = cm.conversation_id;
+
+
+-- name: DeleteConversationMember :exec
+DELETE FROM conversation_members WHERE user_id = @user_id AND chat_id = @chat_id;
+
+
+-- name: InsertGroupConversationMember :exec
+INSERT INTO conversation_members (
+    conversation_id,
+    user_id,
+    chat_id
+)
+SELECT
+    c.id,
+    @user_id::BIGINT,
+    'group_' || c.group_id
+FROM conversations c
+WHERE c.group_id = @group_id
+ON CONFLICT (conversation_id, user_id) DO NOTHING;
\ No newline at end of file
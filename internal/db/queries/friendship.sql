@@ -0,0 +1,117 @@
+-- name: CheckUsersBlocked :one
+SELECT EXISTS(
+    SELECT 1 FROM friendships
+    WHERE status = 'blocked' AND (
+        (user_id = @user_id AND friend_id = @other_user_id)
+        OR
+        (user_id = @other_user_id AND friend_id = @user_id)
+    )
+);
+
+
+-- name: GetFriendshipBetween :one
+SELECT * FROM friendships
+WHERE status <> 'blocked' AND (
+    (user_id = @user_id AND friend_id = @other_user_id)
+    OR
+    (user_id = @other_user_id AND friend_id = @user_id)
+);
+
+
+-- name: InsertFriendRequest :one
+INSERT INTO friendships (user_id, friend_id, status)
+VALUES (@sender_id, @recipient_id, 'pending')
+ON CONFLICT DO NOTHING
+RETURNING *;
+
+
+-- name: AcceptFriendRequest :one
+UPDATE friendships SET status = 'accepted', updated_at = NOW()
+WHERE user_id = @sender_id AND friend_id = @recipient_id AND status = 'pending'
+RETURNING *;
+
+
+-- name: DeleteFriendRequest :execrows
+DELETE FROM friendships
+WHERE user_id = @sender_id AND friend_id = @recipient_id AND status = 'pending';
+
+
+-- name: DeleteFriendship :execrows
+DELETE FROM friendships
+WHERE status = 'accepted' AND (
+    (user_id = @user_id AND friend_id = @friend_id)
+    OR
+    (user_id = @friend_id AND friend_id = @user_id)
+);
+
+
+-- name: GetFriendsPage :many
+SELECT * FROM friendships
+WHERE status = 'accepted'
+    AND (user_id = @user_id OR friend_id = @user_id)
+    AND (sqlc.narg('cursor')::BIGINT IS NULL OR id < sqlc.narg('cursor')::BIGINT)
+ORDER BY id DESC
+LIMIT @result_limit;
+
+
+-- name: GetIncomingFriendRequestsPage :many
+SELECT * FROM friendships
+WHERE status = 'pending'
+    AND friend_id = @user_id
+    AND (sqlc.narg('cursor')::BIGINT IS NULL OR id < sqlc.narg('cursor')::BIGINT)
+ORDER BY id DESC
+LIMIT @result_limit;
+
+
+-- name: GetOutgoingFriendRequestsPage :many
+SELECT * FROM friendships
+WHERE status = 'pending'
+    AND user_id = @user_id
+    AND (sqlc.narg('cursor')::BIGINT IS NULL OR id < sqlc.narg('cursor')::BIGINT)
+ORDER BY id DESC
+LIMIT @result_limit;
+
+
+-- name: InsertUserBlock :execrows
+INSERT INTO friendships (user_id, friend_id, status)
+VALUES (@user_id, @blocked_user_id, 'blocked')
+ON CONFLICT DO NOTHING;
+
+
+-- name: DeleteUserBlock :execrows
+DELETE FROM friendships
+WHERE user_id = @user_id AND friend_id = @blocked_user_id AND status = 'blocked';
+
+
+-- name: DeleteFriendshipsBetween :many
+DELETE FROM friendships
+WHERE status <> 'blocked' AND (
+    (user_id = @user_id AND friend_id = @other_user_id)
+    OR
+    (user_id = @other_user_id AND friend_id = @user_id)
+)
+RETURNING *;
+
+
+-- name: GetBlockedUsersPage :many
+SELECT * FROM friendships
+WHERE status = 'blocked'
+    AND user_id = @user_id
+    AND (sqlc.narg('cursor')::BIGINT IS NULL OR id < sqlc.narg('cursor')::BIGINT)
+ORDER BY id DESC
+LIMIT @result_limit;
+
+
+-- name: GetBatchedUsersBlocked :many
+SELECT
+    p.user_id::BIGINT AS user_id,
+    p.other_user_id::BIGINT AS other_user_id
+FROM UNNEST(@user_ids::BIGINT[], @other_user_ids::BIGINT[]) AS p (user_id, other_user_id)
+WHERE EXISTS(
+    SELECT 1 FROM friendships f
+    WHERE f.status = 'blocked' AND (
+        (f.user_id = p.user_id AND f.friend_id = p.other_user_id)
+        OR
+        (f.user_id = p.other_user_id AND f.friend_id = p.user_id)
+    )
+);
\ No newline at end of file
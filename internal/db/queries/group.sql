@@ -10,6 +10,10 @@ SELECT * FROM groups WHERE id = ANY(@group_ids::BIGINT[]);
 SELECT * FROM group_members WHERE group_id = @group_id;
 
 
+-- name: CheckGroupMember :one
+SELECT EXISTS(SELECT 1 FROM group_members WHERE group_id = @group_id AND user_id = @user_id);
+
+
 -- name: GetBatchedGroupMembers :many
 SELECT 
     gm.*,
@@ -19,4 +23,121 @@ JOIN groups g ON g.id = gm.group_id
 WHERE gm.group_id = ANY(@group_ids::BIGINT[])
 ORDER BY
     is_owner DESC,
-    gm.joined_at ASC;
\ No newline at end of file
+    gm.joined_at ASC;
+
+
+-- name: GetGroupMember :one
+SELECT * FROM group_members WHERE group_id = @group_id AND user_id = @user_id;
+
+
+-- name: GetUserGroups :many
+SELECT g.*
+FROM groups g
+JOIN group_members gm ON gm.group_id = g.id
+WHERE gm.user_id = @user_id
+ORDER BY g.name ASC;
+
+
+-- name: InsertGroup :one
+INSERT INTO groups (
+    name,
+    description,
+    image,
+    created_by
+) VALUES (
+    @name,
+    @description,
+    @image,
+    @created_by
+) RETURNING *;
+
+
+-- name: UpdateGroup :one
+UPDATE groups SET
+    name = @name,
+    description = CASE WHEN @clear_description::BOOLEAN THEN NULL ELSE COALESCE(sqlc.narg('description'), description) END,
+    image = CASE WHEN @clear_image::BOOLEAN THEN NULL ELSE COALESCE(sqlc.narg('image'), image) END,
+    join_policy = @join_policy
+WHERE id = @group_id
+RETURNING *;
+
+
+-- name: DeleteGroup :exec
+DELETE FROM groups WHERE id = @group_id;
+
+
+-- name: InsertGroupMember :execrows
+INSERT INTO group_members (
+    group_id,
+    user_id,
+    is_admin
+) VALUES (
+    @group_id,
+    @user_id,
+    @is_admin
+) ON CONFLICT (group_id, user_id) DO NOTHING;
+
+
+-- name: DeleteGroupMember :execrows
+DELETE FROM group_members WHERE group_id = @group_id AND user_id = @user_id;
+
+
+-- name: SetGroupMemberAdmin :execrows
+UPDATE group_members SET is_admin = @is_admin WHERE group_id = @group_id AND user_id = @user_id;
+
+
+-- name: UpdateGroupOwner :exec
+UPDATE groups SET created_by = @owner_id WHERE id = @group_id;
+
+
+-- name: GetGroupMemberCount :one
+SELECT COUNT(*) FROM group_members WHERE group_id = @group_id;
+
+
+-- name: UpdateGroupPolicies :one
+UPDATE groups SET
+    only_admins_can_send_messages = @only_admins_can_send_messages,
+    only_admins_can_edit_info = @only_admins_can_edit_info,
+    only_admins_can_add_members = @only_admins_can_add_members
+WHERE id = @group_id
+RETURNING *;
+
+
+-- name: GetGroupMembersPage :many
+SELECT
+    gm.*,
+    (g.created_by = gm.user_id) AS is_owner
+FROM group_members gm
+JOIN groups g ON g.id = gm.group_id
+JOIN users u ON u.id = gm.user_id
+WHERE gm.group_id = @group_id
+    AND (NOT @admins_only::BOOLEAN OR gm.is_admin)
+    AND (sqlc.narg('search')::TEXT IS NULL OR u.name ILIKE '%' || sqlc.narg('search')::TEXT || '%' ESCAPE '\' OR u.username ILIKE sqlc.narg('search')::TEXT || '%' ESCAPE '\')
+    AND (
+        sqlc.narg('cursor_id')::BIGINT IS NULL
+        OR (gm.joined_at, gm.id) > (@cursor_joined_at::TIMESTAMPTZ, sqlc.narg('cursor_id')::BIGINT)
+    )
+ORDER BY
+    gm.joined_at ASC,
+    gm.id ASC
+LIMIT @result_limit;
+
+
+-- name: GetBatchedGroupMemberCounts :many
+SELECT
+    group_id,
+    COUNT(*) AS member_count
+FROM group_members
+WHERE group_id = ANY(@group_ids::BIGINT[])
+GROUP BY group_id;
+
+
+-- name: GetBatchedGroupMemberships :many
+SELECT
+    p.group_id::BIGINT AS group_id,
+    p.user_id::BIGINT AS user_id
+FROM UNNEST(@group_ids::BIGINT[], @user_ids::BIGINT[]) AS p (group_id, user_id)
+WHERE EXISTS(
+    SELECT 1 FROM group_members gm
+    WHERE gm.group_id = p.group_id AND gm.user_id = p.user_id
+);
\ No newline at end of file
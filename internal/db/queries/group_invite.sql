@@ -0,0 +1,48 @@
+-- name: InsertGroupInvite :one
+INSERT INTO group_invites (
+    group_id,
+    code,
+    created_by,
+    max_uses,
+    expires_at
+) VALUES (
+    @group_id,
+    @code,
+    @created_by,
+    @max_uses,
+    @expires_at
+) RETURNING *;
+
+
+-- name: GetGroupInviteByID :one
+SELECT * FROM group_invites WHERE id = @invite_id;
+
+
+-- name: GetGroupInviteByCode :one
+SELECT * FROM group_invites WHERE code = @code;
+
+
+-- name: GetGroupInvites :many
+SELECT * FROM group_invites WHERE group_id = @group_id ORDER BY created_at DESC;
+
+
+-- name: RevokeGroupInvite :execrows
+UPDATE group_invites SET revoked_at = NOW() WHERE id = @invite_id AND revoked_at IS NULL;
+
+
+-- name: UseGroupInvite :execrows
+UPDATE group_invites SET use_count = use_count + 1
+WHERE id = @invite_id
+  AND revoked_at IS NULL
+  AND (expires_at IS NULL OR expires_at > NOW())
+  AND (max_uses IS NULL OR use_count < max_uses);
+
+
+-- name: InsertGroupInviteUse :exec
+INSERT INTO group_invite_uses (invite_id, user_id) VALUES (@invite_id, @user_id);
+
+
+-- name: GetBatchedGroupInviteUses :many
+SELECT * FROM group_invite_uses
+WHERE invite_id = ANY(@invite_ids::BIGINT[])
+ORDER BY used_at DESC;
\ No newline at end of file
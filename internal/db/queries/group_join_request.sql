@@ -0,0 +1,34 @@
+-- name: InsertGroupJoinRequest :one
+INSERT INTO group_join_requests (
+    group_id,
+    user_id,
+    invite_id
+) VALUES (
+    @group_id,
+    @user_id,
+    @invite_id
+) RETURNING *;
+
+
+-- name: GetGroupJoinRequestByID :one
+SELECT * FROM group_join_requests WHERE id = @request_id;
+
+
+-- name: GetPendingGroupJoinRequest :one
+SELECT * FROM group_join_requests
+WHERE group_id = @group_id AND user_id = @user_id AND status = 'pending';
+
+
+-- name: GetPendingGroupJoinRequests :many
+SELECT * FROM group_join_requests
+WHERE group_id = @group_id AND status = 'pending'
+ORDER BY created_at ASC;
+
+
+-- name: DecideGroupJoinRequest :one
+UPDATE group_join_requests SET
+    status = @status,
+    decided_by = @decided_by,
+    decided_at = NOW()
+WHERE id = @request_id AND status = 'pending'
+RETURNING *;
\ No newline at end of file
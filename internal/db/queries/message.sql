@@ -1,59 +1,41 @@
 -- name: GetChats :many
-WITH chat_messages AS (
-    SELECT 
-        COALESCE(m.group_id, CASE WHEN m.sender_id = @user_id THEN m.recipient_id ELSE m.sender_id END) AS chat_id, 
-        (m.group_id IS NOT NULL)::BOOLEAN AS is_group_chat, 
-        MAX(m.sent_at) AS last_message_time
-    FROM messages m
-    WHERE (m.sender_id = @user_id OR m.recipient_id = @user_id OR m.group_id IN (
-        SELECT gm.group_id FROM group_members gm WHERE gm.user_id = @user_id
-    ))
-    GROUP BY chat_id, is_group_chat
-),
-last_message AS (
-    SELECT 
-        COALESCE(m.group_id, CASE WHEN m.sender_id = @user_id THEN m.recipient_id ELSE m.sender_id END) AS chat_id, 
-        m.id AS message_id, 
-        m.sender_id,
-        m.recipient_id,
-        m.group_id,
-        m.text_content,
-        m.media,
-        m.sent_at
-    FROM messages m
-    WHERE (m.sender_id = @user_id OR m.recipient_id = @user_id OR m.group_id IN (
-        SELECT gm.group_id FROM group_members gm WHERE gm.user_id = @user_id
-    ))
-),
-unread_count AS (
-    SELECT 
-        COALESCE(m.group_id, CASE WHEN m.sender_id = @user_id THEN m.recipient_id ELSE m.sender_id END) AS chat_id, 
-        COUNT(m.id) AS unread_messages_count
-    FROM messages m
-    LEFT JOIN message_read_receipts mrr ON m.id = mrr.message_id AND mrr.user_id = @user_id
-    WHERE m.sender_id <> @user_id 
-      AND (m.recipient_id = @user_id OR m.group_id IN (
-        SELECT gm.group_id FROM group_members gm WHERE gm.user_id = @user_id
-      )) 
-      AND mrr.message_id IS NULL
-    GROUP BY chat_id
-)
-SELECT 
+SELECT
     cm.chat_id,
-    cm.is_group_chat,
-    COALESCE(g.name, u.name) AS chat_name,
-    lm.message_id AS last_message_id,
-    COALESCE(uc.unread_messages_count, 0) AS unread_message_count
-FROM chat_messages cm
-JOIN last_message lm ON cm.chat_id = lm.chat_id AND cm.last_message_time = lm.sent_at
-LEFT JOIN groups g ON cm.is_group_chat AND cm.chat_id = g.id
-LEFT JOIN users u ON NOT cm.is_group_chat AND cm.chat_id = u.id
-LEFT JOIN unread_count uc ON cm.chat_id = uc.chat_id
-ORDER BY lm.sent_at DESC;
-
+    COALESCE(c.group_id, cm.peer_user_id)::BIGINT AS target_id,
+    (c.group_id IS NOT NULL)::BOOLEAN AS is_group_chat,
+    COALESCE(g.name, u.name)::TEXT AS chat_name,
+    c.last_message_id,
+    cm.unread_count,
+    d.text_content AS draft_text,
+    d.reply_for_message_id AS draft_reply_for_message_id,
+    d.updated_at AS draft_updated_at,
+    cs.archived_at,
+    cs.pin_order,
+    cs.muted_until
+FROM conversation_members cm
+JOIN conversations c ON c.id = cm.conversation_id
+LEFT JOIN groups g ON g.id = c.group_id
+LEFT JOIN users u ON u.id = cm.peer_user_id
+LEFT JOIN chat_drafts d ON d.user_id = cm.user_id AND d.chat_id = cm.chat_id
+LEFT JOIN chat_settings cs ON cs.user_id = cm.user_id AND cs.chat_id = cm.chat_id
+WHERE cm.user_id = @user_id
+    AND (sqlc.narg('archived')::BOOLEAN IS NULL OR (cs.archived_at IS NOT NULL) = sqlc.narg('archived')::BOOLEAN)
+    AND (sqlc.narg('chat_id')::TEXT IS NULL OR cm.chat_id = sqlc.narg('chat_id')::TEXT)
+    AND (sqlc.narg('is_group_chat')::BOOLEAN IS NULL OR (c.group_id IS NOT NULL) = sqlc.narg('is_group_chat')::BOOLEAN)
+    AND (sqlc.narg('search')::TEXT IS NULL OR COALESCE(g.name, u.name) ILIKE '%' || sqlc.narg('search')::TEXT || '%' ESCAPE '\')
+    AND (NOT @unread_only::BOOLEAN OR cm.unread_count > 0)
+    AND (
+        sqlc.narg('cursor_message_id')::BIGINT IS NULL
+        OR COALESCE(cs.pin_order, 2147483647) > @cursor_pin_order::INT
+        OR (COALESCE(cs.pin_order, 2147483647) = @cursor_pin_order::INT AND c.last_message_id < sqlc.narg('cursor_message_id')::BIGINT)
+    )
+ORDER BY
+    COALESCE(cs.pin_order, 2147483647) ASC,
+    c.last_message_id DESC
+LIMIT @result_limit;
 
 
--- name: GetMessages :many
+-- name: GetMessagesBefore :many
 SELECT 
     m.* 
 FROM messages m 
@@ -72,38 +54,27 @@ ORDER BY m.id DESC
 LIMIT @result_limit;
 
 
--- name: CheckMessagesHasNextPage :one
-SELECT EXISTS(
-    SELECT 1 FROM messages m 
-    WHERE
-        CASE 
-            WHEN sqlc.narg('target_user_id')::BIGINT IS NOT NULL THEN
-                (m.sender_id = @current_user_id::BIGINT OR m.recipient_id = @current_user_id::BIGINT)
-                AND
-                (m.sender_id = sqlc.narg('target_user_id')::BIGINT OR m.recipient_id = sqlc.narg('target_user_id')::BIGINT)
-            WHEN sqlc.narg('target_group_id')::BIGINT IS NOT NULL THEN
-                m.group_id = sqlc.narg('target_group_id')::BIGINT
-        END
-        AND
-        (m.id < @cursor_id::BIGINT)
-);
-
-
--- name: CheckMessagesHasPreviousPage :one
-SELECT EXISTS(
-    SELECT 1 FROM messages m 
-    WHERE
-        CASE 
-            WHEN sqlc.narg('target_user_id')::BIGINT IS NOT NULL THEN
-                (m.sender_id = @current_user_id::BIGINT OR m.recipient_id = @current_user_id::BIGINT)
-                AND
-                (m.sender_id = sqlc.narg('target_user_id')::BIGINT OR m.recipient_id = sqlc.narg('target_user_id')::BIGINT)
-            WHEN sqlc.narg('target_group_id')::BIGINT IS NOT NULL THEN
-                m.group_id = sqlc.narg('target_group_id')::BIGINT
-        END
-        AND
-        (m.id > @cursor_id::BIGINT)
-);
+-- name: GetMessagesAfter :many
+SELECT 
+    m.* 
+FROM messages m 
+WHERE
+    CASE 
+        WHEN sqlc.narg('target_user_id')::BIGINT IS NOT NULL THEN
+            (m.sender_id = @current_user_id::BIGINT OR m.recipient_id = @current_user_id::BIGINT)
+            AND
+            (m.sender_id = sqlc.narg('target_user_id')::BIGINT OR m.recipient_id = sqlc.narg('target_user_id')::BIGINT)
+        WHEN sqlc.narg('target_group_id')::BIGINT IS NOT NULL THEN
+            m.group_id = sqlc.narg('target_group_id')::BIGINT
+    END
+    AND
+    (sqlc.narg('cursor_id')::BIGINT IS NULL OR m.id > sqlc.narg('cursor_id')::BIGINT)
+ORDER BY m.id ASC
+LIMIT @result_limit;
+
+
+-- name: GetMessageByID :one
+SELECT * FROM messages WHERE id = @message_id;
 
 
 -- name: GetBatchedMessages :many
@@ -119,7 +90,9 @@ INSERT INTO messages (
     text_content,
     media,
     location,
-    reply_for_message_id
+    reply_for_message_id,
+    client_message_id,
+    system_event
 ) VALUES (
     @sender_id,
     @recipient_id,
@@ -128,5 +101,56 @@ INSERT INTO messages (
     @text_content,
     @media,
     @location,
-    @reply_for_message_id
-) RETURNING *;
\ No newline at end of file
+    @reply_for_message_id,
+    @client_message_id,
+    @system_event
+) ON CONFLICT (sender_id, client_message_id) DO NOTHING 
+RETURNING *;
+
+
+-- name: GetMessageByClientMessageID :one
+SELECT * FROM messages WHERE sender_id = @sender_id AND client_message_id = @client_message_id;
+
+
+-- name: InsertChatReadReceipts :execrows
+INSERT INTO message_read_receipts (
+    message_id,
+    user_id,
+    read_at
+)
+SELECT
+    m.id,
+    @user_id::BIGINT,
+    NOW()
+FROM messages m
+WHERE
+    CASE
+        WHEN sqlc.narg('target_user_id')::BIGINT IS NOT NULL THEN
+            m.sender_id = sqlc.narg('target_user_id')::BIGINT AND m.recipient_id = @user_id::BIGINT
+        WHEN sqlc.narg('target_group_id')::BIGINT IS NOT NULL THEN
+            m.group_id = sqlc.narg('target_group_id')::BIGINT AND m.sender_id <> @user_id::BIGINT
+    END
+    AND
+    (sqlc.narg('after_message_id')::BIGINT IS NULL OR m.id > sqlc.narg('after_message_id')::BIGINT)
+ON CONFLICT (message_id, user_id) DO NOTHING;
+
+
+-- name: GetConversationChatPreviews :many
+SELECT
+    cm.user_id,
+    cm.chat_id,
+    COALESCE(c.group_id, cm.peer_user_id)::BIGINT AS target_id,
+    (c.group_id IS NOT NULL)::BOOLEAN AS is_group_chat,
+    c.last_message_id,
+    cm.unread_count,
+    d.text_content AS draft_text,
+    d.reply_for_message_id AS draft_reply_for_message_id,
+    d.updated_at AS draft_updated_at,
+    cs.archived_at,
+    cs.pin_order,
+    cs.muted_until
+FROM conversation_members cm
+JOIN conversations c ON c.id = cm.conversation_id
+LEFT JOIN chat_drafts d ON d.user_id = cm.user_id AND d.chat_id = cm.chat_id
+LEFT JOIN chat_settings cs ON cs.user_id = cm.user_id AND cs.chat_id = cm.chat_id
+WHERE c.conversation_key = @conversation_key;
\ No newline at end of file
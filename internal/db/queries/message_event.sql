@@ -0,0 +1,25 @@
+-- name: InsertMessageEvents :many
+INSERT INTO message_events (
+    user_id,
+    chat_id,
+    payload
+)
+SELECT
+    unnest(@user_ids::BIGINT[]),
+    unnest(@chat_ids::TEXT[]),
+    unnest(@payloads::JSONB[])
+RETURNING id, user_id;
+
+
+-- name: GetMessageEventsAfter :many
+SELECT * FROM message_events
+WHERE
+    user_id = @user_id
+    AND id > @after_id
+    AND (sqlc.narg('chat_id')::TEXT IS NULL OR chat_id = sqlc.narg('chat_id')::TEXT)
+ORDER BY id ASC
+LIMIT @result_limit;
+
+
+-- name: DeleteMessageEventsBefore :execrows
+DELETE FROM message_events WHERE created_at < @before;
\ No newline at end of file
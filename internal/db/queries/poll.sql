@@ -0,0 +1,72 @@
+-- name: InsertPoll :one
+INSERT INTO polls (
+    message_id,
+    question,
+    multiple_choice,
+    anonymous,
+    closes_at
+) VALUES (
+    @message_id,
+    @question,
+    @multiple_choice,
+    @anonymous,
+    @closes_at
+)
+RETURNING *;
+
+
+-- name: InsertPollOption :exec
+INSERT INTO poll_options (
+    poll_id,
+    option_text,
+    sort_index
+) VALUES (
+    @poll_id,
+    @option_text,
+    @sort_index
+);
+
+
+-- name: GetPollByMessageID :one
+SELECT * FROM polls WHERE message_id = @message_id;
+
+
+-- name: GetBatchedPolls :many
+SELECT * FROM polls WHERE message_id = ANY(@message_ids::BIGINT[]);
+
+
+-- name: GetBatchedPollOptions :many
+SELECT
+    o.*,
+    COALESCE(
+        ARRAY_AGG(v.user_id ORDER BY v.voted_at) FILTER (WHERE v.user_id IS NOT NULL),
+        '{}'
+    )::BIGINT[] AS voter_ids
+FROM poll_options o
+LEFT JOIN poll_votes v ON v.poll_option_id = o.id
+WHERE o.poll_id = ANY(@poll_ids::BIGINT[])
+GROUP BY o.id
+ORDER BY
+    o.poll_id ASC,
+    o.sort_index ASC;
+
+
+-- name: InsertPollVote :exec
+INSERT INTO poll_votes (
+    poll_id,
+    poll_option_id,
+    user_id
+) VALUES (
+    @poll_id,
+    @poll_option_id,
+    @user_id
+)
+ON CONFLICT (poll_option_id, user_id) DO NOTHING;
+
+
+-- name: DeletePollVotes :execrows
+DELETE FROM poll_votes WHERE poll_id = @poll_id AND user_id = @user_id;
+
+
+-- name: LockPoll :exec
+SELECT id FROM polls WHERE id = @id FOR UPDATE;
\ No newline at end of file
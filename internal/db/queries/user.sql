@@ -90,3 +90,38 @@ WHERE id = @user_id;
 -- name: UpdateUserOnlineStatus :exec
 UPDATE users SET online = @online WHERE id = @user_id;
 
+
+-- name: SearchUserIDs :many
+WITH ranked AS (
+    SELECT
+        u.id,
+        GREATEST(similarity(u.username, @search::TEXT), similarity(u.name, @search::TEXT))
+        + CASE WHEN EXISTS(
+            SELECT 1 FROM friendships f
+            WHERE f.status = 'accepted' AND (
+                (f.user_id = @user_id AND f.friend_id = u.id)
+                OR
+                (f.user_id = u.id AND f.friend_id = @user_id)
+            )
+        ) THEN 0.5 ELSE 0 END
+        + CASE WHEN EXISTS(
+            SELECT 1 FROM group_members gm
+            JOIN group_members ugm ON ugm.group_id = gm.group_id
+            WHERE gm.user_id = u.id AND ugm.user_id = @user_id
+        ) THEN 0.25 ELSE 0 END AS search_rank
+    FROM users u
+    WHERE u.id <> @user_id
+        AND u.deleted_at IS NULL
+        AND (u.username % @search::TEXT OR u.name % @search::TEXT OR u.username ILIKE @prefix_pattern::TEXT ESCAPE '\')
+        AND NOT EXISTS(
+            SELECT 1 FROM friendships f
+            WHERE f.status = 'blocked' AND (
+                (f.user_id = @user_id AND f.friend_id = u.id)
+                OR
+                (f.user_id = u.id AND f.friend_id = @user_id)
+            )
+        )
+)
+SELECT id FROM ranked
+ORDER BY search_rank DESC, id ASC
+LIMIT @result_limit OFFSET @result_offset;
\ No newline at end of file
@@ -0,0 +1,35 @@
+-- name: UpsertUserLocation :exec
+INSERT INTO user_locations (user_id, location, visible, updated_at)
+VALUES (@user_id, @location, @visible, NOW())
+ON CONFLICT (user_id) DO UPDATE SET
+    location = EXCLUDED.location,
+    visible = EXCLUDED.visible,
+    updated_at = NOW();
+
+
+-- name: GetUserLocation :one
+SELECT * FROM user_locations WHERE user_id = @user_id;
+
+
+-- name: GetNearbyUsers :many
+SELECT
+    ul.user_id,
+    ST_Distance(ul.location, @location::GEOGRAPHY)::FLOAT8 AS distance
+FROM user_locations ul
+JOIN users u ON u.id = ul.user_id
+WHERE ul.visible
+    AND ul.location IS NOT NULL
+    AND ul.user_id <> @user_id
+    AND ul.updated_at > @updated_after::TIMESTAMPTZ
+    AND u.deleted_at IS NULL
+    AND ST_DWithin(ul.location, @location::GEOGRAPHY, @radius_meters::FLOAT8)
+    AND NOT EXISTS(
+        SELECT 1 FROM friendships f
+        WHERE f.status = 'blocked' AND (
+            (f.user_id = @user_id AND f.friend_id = ul.user_id)
+            OR
+            (f.user_id = ul.user_id AND f.friend_id = @user_id)
+        )
+    )
+ORDER BY distance ASC
+LIMIT @result_limit;
\ No newline at end of file
@@ -1,4 +1,5 @@
 CREATE EXTENSION postgis;
+CREATE EXTENSION pg_trgm;
 
 
 CREATE TABLE roles (
@@ -56,6 +57,10 @@ CREATE TABLE users (
     CONSTRAINT users_unique_email UNIQUE (email)
 );
 
+-- Trigram indexes used by the user search.
+CREATE INDEX users_username_trgm_idx ON users USING GIN (username gin_trgm_ops);
+CREATE INDEX users_name_trgm_idx ON users USING GIN (name gin_trgm_ops);
+
 
 
 CREATE TABLE user_roles (
@@ -117,19 +122,40 @@ CREATE TABLE email_verification_tokens (
 
 
 
+CREATE TABLE user_locations (
+    user_id BIGINT NOT NULL,
+    location GEOGRAPHY(POINT), -- Last known location of the user, cleared when location sharing is turned off.
+    visible BOOLEAN NOT NULL DEFAULT FALSE, -- Opt in to be shown to nearby users.
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (user_id),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX user_locations_location_idx ON user_locations USING GIST (location);
+
+
+
 CREATE TABLE friendships (
     id BIGINT GENERATED ALWAYS AS IDENTITY,
     user_id BIGINT NOT NULL,
     friend_id BIGINT NOT NULL,
-    status TEXT NOT NULL, -- e.g., 'pending', 'accepted', 'blocked'
+    status TEXT NOT NULL, -- e.g., 'pending', 'accepted', 'blocked', user_id is the sender of the request or the blocker
     created_at TIMESTAMPTZ DEFAULT NOW(),
     updated_at TIMESTAMPTZ DEFAULT NOW(),
 
     PRIMARY KEY (id),
     FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
-    FOREIGN KEY (friend_id) REFERENCES users (id) ON DELETE CASCADE
+    FOREIGN KEY (friend_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT friendships_not_self CHECK (user_id <> friend_id)
 );
 
+-- A single friendship per pair of users regardless of who sent the request.
+CREATE UNIQUE INDEX friendships_unique_pair_idx ON friendships (LEAST(user_id, friend_id), GREATEST(user_id, friend_id)) WHERE status <> 'blocked';
+
+-- Blocks are directional so both users of a pair can block each other.
+CREATE UNIQUE INDEX friendships_unique_block_idx ON friendships (user_id, friend_id) WHERE status = 'blocked';
+
 
 
 CREATE TABLE groups (
@@ -139,6 +165,10 @@ CREATE TABLE groups (
     description TEXT,
     created_by BIGINT NOT NULL,
     created_at TIMESTAMPTZ DEFAULT NOW(),
+    join_policy TEXT NOT NULL DEFAULT 'open', -- 'open' or 'approval_required'
+    only_admins_can_send_messages BOOLEAN NOT NULL DEFAULT FALSE,
+    only_admins_can_edit_info BOOLEAN NOT NULL DEFAULT TRUE,
+    only_admins_can_add_members BOOLEAN NOT NULL DEFAULT TRUE,
 
     PRIMARY KEY (id),
     FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL
@@ -162,12 +192,142 @@ CREATE TABLE group_members (
 
 
 
+CREATE TABLE group_invites (
+    id BIGINT GENERATED ALWAYS AS IDENTITY,
+    group_id BIGINT NOT NULL,
+    code TEXT NOT NULL,
+    created_by BIGINT NOT NULL,
+    max_uses INT, -- null when the invite can be used any number of times
+    use_count INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (id),
+    FOREIGN KEY (group_id) REFERENCES groups (id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT group_invites_unique_code UNIQUE (code)
+);
+
+CREATE INDEX group_invites_group_id_idx ON group_invites (group_id);
+
+
+
+CREATE TABLE group_invite_uses (
+    id BIGINT GENERATED ALWAYS AS IDENTITY,
+    invite_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    used_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (id),
+    FOREIGN KEY (invite_id) REFERENCES group_invites (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX group_invite_uses_invite_id_idx ON group_invite_uses (invite_id);
+
+
+
+
+CREATE TABLE group_join_requests (
+    id BIGINT GENERATED ALWAYS AS IDENTITY,
+    group_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    invite_id BIGINT, -- invite the request was made through
+    status TEXT NOT NULL DEFAULT 'pending', -- 'pending', 'approved' or 'rejected'
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    decided_by BIGINT,
+    decided_at TIMESTAMPTZ,
+
+    PRIMARY KEY (id),
+    FOREIGN KEY (group_id) REFERENCES groups (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (invite_id) REFERENCES group_invites (id) ON DELETE SET NULL,
+    FOREIGN KEY (decided_by) REFERENCES users (id) ON DELETE SET NULL
+);
+
+-- A user can only have one pending request per group.
+CREATE UNIQUE INDEX group_join_requests_pending_idx ON group_join_requests (group_id, user_id) WHERE status = 'pending';
+
+
+
+
+CREATE TABLE channels (
+    id BIGINT GENERATED ALWAYS AS IDENTITY,
+    handle TEXT, -- unique handle used to discover public channels, null for private channels
+    name TEXT NOT NULL,
+    description TEXT,
+    image TEXT,
+    is_public BOOLEAN NOT NULL DEFAULT FALSE,
+    invite_code TEXT NOT NULL, -- code of the invite link, replaced when the link is reset
+    created_by BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (id),
+    FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT channels_unique_handle UNIQUE (handle),
+    CONSTRAINT channels_unique_invite_code UNIQUE (invite_code)
+);
+
+
+
+CREATE TABLE channel_members (
+    id BIGINT GENERATED ALWAYS AS IDENTITY,
+    channel_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    role TEXT NOT NULL DEFAULT 'subscriber', -- 'owner', 'admin' or 'subscriber'
+    joined_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (id),
+    FOREIGN KEY (channel_id) REFERENCES channels (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT channel_members_unique_channel_user UNIQUE (channel_id, user_id)
+);
+
+CREATE INDEX channel_members_user_id_idx ON channel_members (user_id);
+
+
+
+CREATE TABLE channel_posts (
+    id BIGINT GENERATED ALWAYS AS IDENTITY,
+    channel_id BIGINT NOT NULL,
+    author_id BIGINT NOT NULL,
+    text_content TEXT,
+    media TEXT,
+    posted_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    PRIMARY KEY (id),
+    FOREIGN KEY (channel_id) REFERENCES channels (id) ON DELETE CASCADE,
+    FOREIGN KEY (author_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX channel_posts_channel_id_idx ON channel_posts (channel_id, id);
+
+
+
+CREATE TABLE channel_post_reactions (
+    id BIGINT GENERATED ALWAYS AS IDENTITY,
+    post_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    emoji TEXT NOT NULL,
+    reacted_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (id),
+    FOREIGN KEY (post_id) REFERENCES channel_posts (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT channel_post_reactions_unique_post_user UNIQUE (post_id, user_id)
+);
+
+
+
+
 CREATE TABLE messages (
     id BIGINT GENERATED ALWAYS AS IDENTITY,
     sender_id BIGINT NOT NULL,   
     recipient_id BIGINT, -- recipient id will be null if the message is sent to a group              
     group_id BIGINT, -- group id will be null if the message is a direct message           
-    message_type TEXT NOT NULL, -- Type of message: 'system', 'text', 'audio', 'video', 'document', 'location',         
+    message_type TEXT NOT NULL, -- Type of message: 'system', 'text', 'audio', 'video', 'document', 'location', 'poll',         
     text_content TEXT,                  
     media TEXT,
     location GEOGRAPHY(POINT), -- Location for location and live location messages.
@@ -175,15 +335,34 @@ CREATE TABLE messages (
     sent_at TIMESTAMPTZ DEFAULT NOW(),
     deleted_at TIMESTAMPTZ,
     deleted_by BIGINT,
+    client_message_id UUID, -- Client generated id used to deduplicate retried sends
+    system_event JSONB, -- Structured event of a system message e.g. member added, group renamed
 
     PRIMARY KEY (id),
     FOREIGN KEY (sender_id) REFERENCES users (id),
     FOREIGN KEY (recipient_id) REFERENCES users (id),
     FOREIGN KEY (group_id) REFERENCES groups (id) ON DELETE CASCADE,
     FOREIGN KEY (reply_for_message_id) REFERENCES messages (id) ON DELETE CASCADE,
-    FOREIGN KEY (deleted_by) REFERENCES users (id)
+    FOREIGN KEY (deleted_by) REFERENCES users (id),
+    CONSTRAINT messages_unique_sender_client_message UNIQUE (sender_id, client_message_id)
+);
+
+
+
+
+CREATE TABLE message_events (
+    id BIGINT GENERATED ALWAYS AS IDENTITY,
+    user_id BIGINT NOT NULL, -- Receiver of the event
+    chat_id TEXT NOT NULL, -- Chat id of the event as seen by the receiver e.g. 'direct_1', 'group_1'
+    payload JSONB NOT NULL, -- The serialized message event, replayed to clients that missed it
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (id),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
 );
 
+CREATE INDEX message_events_user_id_idx ON message_events (user_id, id);
+CREATE INDEX message_events_created_at_idx ON message_events (created_at);
 
 
 
@@ -217,6 +396,117 @@ CREATE TABLE message_read_receipts (
 
 
 
+CREATE TABLE polls (
+    id BIGINT GENERATED ALWAYS AS IDENTITY,
+    message_id BIGINT NOT NULL,
+    question TEXT NOT NULL,
+    multiple_choice BOOLEAN NOT NULL DEFAULT FALSE,
+    anonymous BOOLEAN NOT NULL DEFAULT FALSE,
+    closes_at TIMESTAMPTZ, -- Poll does not accept votes after this time, never closes if null
+
+    PRIMARY KEY (id),
+    FOREIGN KEY (message_id) REFERENCES messages (id) ON DELETE CASCADE,
+    CONSTRAINT polls_unique_message UNIQUE (message_id)
+);
+
+
+
+CREATE TABLE poll_options (
+    id BIGINT GENERATED ALWAYS AS IDENTITY,
+    poll_id BIGINT NOT NULL,
+    option_text TEXT NOT NULL,
+    sort_index INT NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (id),
+    FOREIGN KEY (poll_id) REFERENCES polls (id) ON DELETE CASCADE
+);
+
+
+
+CREATE TABLE poll_votes (
+    id BIGINT GENERATED ALWAYS AS IDENTITY,
+    poll_id BIGINT NOT NULL,
+    poll_option_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    voted_at TIMESTAMPTZ DEFAULT NOW(),
+
+    PRIMARY KEY (id),
+    FOREIGN KEY (poll_id) REFERENCES polls (id) ON DELETE CASCADE,
+    FOREIGN KEY (poll_option_id) REFERENCES poll_options (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT poll_votes_unique_option_user UNIQUE (poll_option_id, user_id)
+);
+
+
+
+CREATE TABLE chat_drafts (
+    id BIGINT GENERATED ALWAYS AS IDENTITY,
+    user_id BIGINT NOT NULL,
+    chat_id TEXT NOT NULL, -- Chat id as used by the api e.g. 'direct_1', 'group_1'
+    text_content TEXT NOT NULL,
+    reply_for_message_id BIGINT,
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+
+    PRIMARY KEY (id),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (reply_for_message_id) REFERENCES messages (id) ON DELETE SET NULL,
+    CONSTRAINT chat_drafts_unique_user_chat UNIQUE (user_id, chat_id)
+);
+
+
+
+CREATE TABLE chat_settings (
+    id BIGINT GENERATED ALWAYS AS IDENTITY,
+    user_id BIGINT NOT NULL,
+    chat_id TEXT NOT NULL, -- Chat id as used by the api e.g. 'direct_1', 'group_1'
+    archived_at TIMESTAMPTZ, -- Chat is archived if not null
+    pin_order INT, -- Chat is pinned if not null, pinned chats are sorted by this in ascending order
+    muted_until TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+
+    PRIMARY KEY (id),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT chat_settings_unique_user_chat UNIQUE (user_id, chat_id)
+);
+
+
+
+CREATE TABLE conversations (
+    id BIGINT GENERATED ALWAYS AS IDENTITY,
+    conversation_key TEXT NOT NULL, -- 'group_<group id>' or 'direct_<lower user id>_<higher user id>'
+    group_id BIGINT, -- group id will be null for direct conversations
+    last_message_id BIGINT NOT NULL,
+    last_activity_at TIMESTAMPTZ NOT NULL,
+    event_sequence BIGINT NOT NULL DEFAULT 0, -- Incremented for every message event of the conversation
+
+    PRIMARY KEY (id),
+    FOREIGN KEY (group_id) REFERENCES groups (id) ON DELETE CASCADE,
+    FOREIGN KEY (last_message_id) REFERENCES messages (id) ON DELETE CASCADE,
+    CONSTRAINT conversations_unique_key UNIQUE (conversation_key)
+);
+
+
+
+CREATE TABLE conversation_members (
+    id BIGINT GENERATED ALWAYS AS IDENTITY,
+    conversation_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    chat_id TEXT NOT NULL, -- Chat id of the conversation as seen by the user e.g. 'direct_1', 'group_1'
+    peer_user_id BIGINT, -- The other user of a direct conversation
+    unread_count INT NOT NULL DEFAULT 0,
+    last_read_message_id BIGINT,
+
+    PRIMARY KEY (id),
+    FOREIGN KEY (conversation_id) REFERENCES conversations (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (peer_user_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (last_read_message_id) REFERENCES messages (id) ON DELETE SET NULL,
+    CONSTRAINT conversation_members_unique_conversation_user UNIQUE (conversation_id, user_id),
+    CONSTRAINT conversation_members_unique_user_chat UNIQUE (user_id, chat_id)
+);
+
+
+
 CREATE TABLE posts (
     id BIGINT GENERATED ALWAYS AS IDENTITY,
     caption TEXT,
@@ -253,6 +253,76 @@ func (q *Queries) InsertUserRole(ctx context.Context, arg InsertUserRoleParams)
 	return err
 }
 
+const SearchUserIDs = `-- name: SearchUserIDs :many
+WITH ranked AS (
+    SELECT
+        u.id,
+        GREATEST(similarity(u.username, $1::TEXT), similarity(u.name, $1::TEXT))
+        + CASE WHEN EXISTS(
+            SELECT 1 FROM friendships f
+            WHERE f.status = 'accepted' AND (
+                (f.user_id = $2 AND f.friend_id = u.id)
+                OR
+                (f.user_id = u.id AND f.friend_id = $2)
+            )
+        ) THEN 0.5 ELSE 0 END
+        + CASE WHEN EXISTS(
+            SELECT 1 FROM group_members gm
+            JOIN group_members ugm ON ugm.group_id = gm.group_id
+            WHERE gm.user_id = u.id AND ugm.user_id = $2
+        ) THEN 0.25 ELSE 0 END AS search_rank
+    FROM users u
+    WHERE u.id <> $2
+        AND u.deleted_at IS NULL
+        AND (u.username % $1::TEXT OR u.name % $1::TEXT OR u.username ILIKE $5::TEXT ESCAPE '\')
+        AND NOT EXISTS(
+            SELECT 1 FROM friendships f
+            WHERE f.status = 'blocked' AND (
+                (f.user_id = $2 AND f.friend_id = u.id)
+                OR
+                (f.user_id = u.id AND f.friend_id = $2)
+            )
+        )
+)
+SELECT id FROM ranked
+ORDER BY search_rank DESC, id ASC
+LIMIT $3 OFFSET $4
+`
+
+type SearchUserIDsParams struct {
+	Search        string
+	UserID        int64
+	ResultLimit   int64
+	ResultOffset  int64
+	PrefixPattern string
+}
+
+func (q *Queries) SearchUserIDs(ctx context.Context, arg SearchUserIDsParams) ([]int64, error) {
+	rows, err := q.db.Query(ctx, SearchUserIDs,
+		arg.Search,
+		arg.UserID,
+		arg.ResultLimit,
+		arg.ResultOffset,
+		arg.PrefixPattern,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const UpdateUser = `-- name: UpdateUser :exec
 UPDATE users SET
     username = $1,
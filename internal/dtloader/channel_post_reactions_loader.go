@@ -0,0 +1,49 @@
+package dtloader
+
+import (
+	"context"
+
+	"github.com/graph-gophers/dataloader/v7"
+
+	"github.com/thanishsid/dingilink-server/internal/db"
+	"github.com/thanishsid/dingilink-server/internal/model"
+)
+
+type ChannelPostReactionsLoader = *dataloader.Loader[int64, []*model.ChannelPostReactionCount]
+
+func newChannelPostReactionsLoader(d db.DBQ) ChannelPostReactionsLoader {
+	cache := &dataloader.NoCache[int64, []*model.ChannelPostReactionCount]{}
+
+	loader := dataloader.NewBatchedLoader(func(ctx context.Context, ids []int64) []*dataloader.Result[[]*model.ChannelPostReactionCount] {
+		results := make([]*dataloader.Result[[]*model.ChannelPostReactionCount], len(ids))
+
+		res, err := d.GetBatchedChannelPostReactionCounts(ctx, ids)
+		if err != nil {
+			for idx := range ids {
+				results[idx] = &dataloader.Result[[]*model.ChannelPostReactionCount]{
+					Error: err,
+				}
+			}
+			return results
+		}
+
+		reactionsMap := make(map[int64][]*model.ChannelPostReactionCount, len(ids))
+
+		for _, r := range res {
+			reactionsMap[r.PostID] = append(reactionsMap[r.PostID], &model.ChannelPostReactionCount{
+				Emoji: r.Emoji,
+				Count: r.ReactionCount,
+			})
+		}
+
+		for idx, id := range ids {
+			results[idx] = &dataloader.Result[[]*model.ChannelPostReactionCount]{
+				Data: reactionsMap[id],
+			}
+		}
+
+		return results
+	}, dataloader.WithCache(cache))
+
+	return loader
+}
@@ -27,17 +27,31 @@ import (
 
 type Dataloader struct {
 	user         UserLoader
+	usersBlocked UsersBlockedLoader
 	group        GroupLoader
 	groupMembers GroupMembersLoader
+	membership   GroupMembershipLoader
+	memberCount  GroupMemberCountLoader
+	inviteUses   GroupInviteUsesLoader
+	channelCount ChannelMemberCountLoader
+	reactions    ChannelPostReactionsLoader
 	message      MessageLoader
+	poll         PollLoader
 }
 
 func NewDataloader(d db.DBQ) *Dataloader {
 	return &Dataloader{
 		user:         newUserLoader(d),
+		usersBlocked: newUsersBlockedLoader(d),
 		group:        newGroupLoader(d),
 		groupMembers: newGroupMembersLoader(d),
+		membership:   newGroupMembershipLoader(d),
+		memberCount:  newGroupMemberCountLoader(d),
+		inviteUses:   newGroupInviteUsesLoader(d),
+		channelCount: newChannelMemberCountLoader(d),
+		reactions:    newChannelPostReactionsLoader(d),
 		message:      newMessageLoader(d),
+		poll:         newPollLoader(d),
 	}
 }
 
@@ -46,6 +60,11 @@ func (d *Dataloader) GetUser(ctx context.Context, userID int64) (*model.User, er
 	return d.user.Load(ctx, userID)()
 }
 
+// Check if either of two users has blocked the other.
+func (d *Dataloader) GetUsersBlocked(ctx context.Context, userID int64, otherUserID int64) (bool, error) {
+	return d.usersBlocked.Load(ctx, UserPair{UserID: userID, OtherUserID: otherUserID})()
+}
+
 // Get a group by id.
 func (d *Dataloader) GetGroup(ctx context.Context, groupID int64) (*model.Group, error) {
 	return d.group.Load(ctx, groupID)()
@@ -56,7 +75,37 @@ func (d *Dataloader) GetGroupMembers(ctx context.Context, groupID int64) ([]*mod
 	return d.groupMembers.Load(ctx, groupID)()
 }
 
+// Check if a user is a member of a group.
+func (d *Dataloader) GetIsGroupMember(ctx context.Context, groupID int64, userID int64) (bool, error) {
+	return d.membership.Load(ctx, GroupMembership{GroupID: groupID, UserID: userID})()
+}
+
+// Get the number of members of a group by the group id.
+func (d *Dataloader) GetGroupMemberCount(ctx context.Context, groupID int64) (int64, error) {
+	return d.memberCount.Load(ctx, groupID)()
+}
+
+// Get the uses of a group invite by the invite id.
+func (d *Dataloader) GetGroupInviteUses(ctx context.Context, inviteID int64) ([]*model.GroupInviteUse, error) {
+	return d.inviteUses.Load(ctx, inviteID)()
+}
+
+// Get the number of members of a channel by the channel id.
+func (d *Dataloader) GetChannelMemberCount(ctx context.Context, channelID int64) (int64, error) {
+	return d.channelCount.Load(ctx, channelID)()
+}
+
+// Get the reaction counts of a channel post by the post id.
+func (d *Dataloader) GetChannelPostReactions(ctx context.Context, postID int64) ([]*model.ChannelPostReactionCount, error) {
+	return d.reactions.Load(ctx, postID)()
+}
+
 // Get a message by id.
 func (d *Dataloader) GetMessage(ctx context.Context, messageID int64) (model.Message, error) {
 	return d.message.Load(ctx, messageID)()
 }
+
+// Get a poll by the id of its message.
+func (d *Dataloader) GetPoll(ctx context.Context, messageID int64) (*model.Poll, error) {
+	return d.poll.Load(ctx, messageID)()
+}
@@ -0,0 +1,50 @@
+package dtloader
+
+import (
+	"context"
+
+	"github.com/graph-gophers/dataloader/v7"
+
+	"github.com/thanishsid/dingilink-server/internal/db"
+	"github.com/thanishsid/dingilink-server/internal/model"
+)
+
+type GroupInviteUsesLoader = *dataloader.Loader[int64, []*model.GroupInviteUse]
+
+func newGroupInviteUsesLoader(d db.DBQ) GroupInviteUsesLoader {
+	cache := &dataloader.NoCache[int64, []*model.GroupInviteUse]{}
+
+	loader := dataloader.NewBatchedLoader(func(ctx context.Context, ids []int64) []*dataloader.Result[[]*model.GroupInviteUse] {
+		results := make([]*dataloader.Result[[]*model.GroupInviteUse], len(ids))
+
+		res, err := d.GetBatchedGroupInviteUses(ctx, ids)
+		if err != nil {
+			for idx := range ids {
+				results[idx] = &dataloader.Result[[]*model.GroupInviteUse]{
+					Error: err,
+				}
+			}
+			return results
+		}
+
+		usesMap := make(map[int64][]*model.GroupInviteUse, len(ids))
+
+		for _, u := range res {
+			usesMap[u.InviteID] = append(usesMap[u.InviteID], &model.GroupInviteUse{
+				ID:     u.ID,
+				UserID: u.UserID,
+				UsedAt: u.UsedAt,
+			})
+		}
+
+		for idx, id := range ids {
+			results[idx] = &dataloader.Result[[]*model.GroupInviteUse]{
+				Data: usesMap[id],
+			}
+		}
+
+		return results
+	}, dataloader.WithCache(cache))
+
+	return loader
+}
@@ -37,6 +37,12 @@ func newGroupLoader(d db.DBQ) GroupLoader {
 					Description: g.Description,
 					Image:       g.Image,
 					CreatedBy:   g.CreatedBy,
+					JoinPolicy:  model.GroupJoinPolicy(g.JoinPolicy),
+					Policies: model.GroupPolicies{
+						OnlyAdminsCanSendMessages: g.OnlyAdminsCanSendMessages,
+						OnlyAdminsCanEditInfo:     g.OnlyAdminsCanEditInfo,
+						OnlyAdminsCanAddMembers:   g.OnlyAdminsCanAddMembers,
+					},
 				},
 			}
 		}
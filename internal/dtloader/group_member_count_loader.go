@@ -0,0 +1,45 @@
+package dtloader
+
+import (
+	"context"
+
+	"github.com/graph-gophers/dataloader/v7"
+
+	"github.com/thanishsid/dingilink-server/internal/db"
+)
+
+type GroupMemberCountLoader = *dataloader.Loader[int64, int64]
+
+func newGroupMemberCountLoader(d db.DBQ) GroupMemberCountLoader {
+	cache := &dataloader.NoCache[int64, int64]{}
+
+	loader := dataloader.NewBatchedLoader(func(ctx context.Context, ids []int64) []*dataloader.Result[int64] {
+		results := make([]*dataloader.Result[int64], len(ids))
+
+		res, err := d.GetBatchedGroupMemberCounts(ctx, ids)
+		if err != nil {
+			for idx := range ids {
+				results[idx] = &dataloader.Result[int64]{
+					Error: err,
+				}
+			}
+			return results
+		}
+
+		countsMap := make(map[int64]int64, len(ids))
+
+		for _, c := range res {
+			countsMap[c.GroupID] = c.MemberCount
+		}
+
+		for idx, id := range ids {
+			results[idx] = &dataloader.Result[int64]{
+				Data: countsMap[id],
+			}
+		}
+
+		return results
+	}, dataloader.WithCache(cache))
+
+	return loader
+}
@@ -0,0 +1,62 @@
+package dtloader
+
+import (
+	"context"
+
+	"github.com/graph-gophers/dataloader/v7"
+
+	"github.com/thanishsid/dingilink-server/internal/db"
+)
+
+// Group and user checked for membership.
+type GroupMembership struct {
+	GroupID int64
+	UserID  int64
+}
+
+type GroupMembershipLoader = *dataloader.Loader[GroupMembership, bool]
+
+func newGroupMembershipLoader(d db.DBQ) GroupMembershipLoader {
+	cache := &dataloader.NoCache[GroupMembership, bool]{}
+
+	loader := dataloader.NewBatchedLoader(func(ctx context.Context, keys []GroupMembership) []*dataloader.Result[bool] {
+		results := make([]*dataloader.Result[bool], len(keys))
+
+		groupIDs := make([]int64, len(keys))
+		userIDs := make([]int64, len(keys))
+
+		for idx, k := range keys {
+			groupIDs[idx] = k.GroupID
+			userIDs[idx] = k.UserID
+		}
+
+		res, err := d.GetBatchedGroupMemberships(ctx, db.GetBatchedGroupMembershipsParams{
+			GroupIds: groupIDs,
+			UserIds:  userIDs,
+		})
+		if err != nil {
+			for idx := range keys {
+				results[idx] = &dataloader.Result[bool]{
+					Error: err,
+				}
+			}
+			return results
+		}
+
+		memberMap := make(map[GroupMembership]bool, len(res))
+
+		for _, m := range res {
+			memberMap[GroupMembership{GroupID: m.GroupID, UserID: m.UserID}] = true
+		}
+
+		for idx, k := range keys {
+			results[idx] = &dataloader.Result[bool]{
+				Data: memberMap[k],
+			}
+		}
+
+		return results
+	}, dataloader.WithCache(cache))
+
+	return loader
+}
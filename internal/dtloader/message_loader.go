@@ -43,6 +43,7 @@ func newMessageLoader(d db.DBQ) MessageLoader {
 				SentAt:            m.SentAt,
 				DeletedAt:         m.DeletedAt,
 				DeletedBy:         m.DeletedBy,
+				SystemEvent:       m.SystemEvent,
 			}.Build()
 			if err != nil {
 				msgMap[m.ID] = &dataloader.Result[model.Message]{
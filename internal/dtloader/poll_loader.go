@@ -0,0 +1,94 @@
+package dtloader
+
+import (
+	"context"
+
+	"github.com/graph-gophers/dataloader/v7"
+
+	"github.com/thanishsid/dingilink-server/internal/db"
+	"github.com/thanishsid/dingilink-server/internal/model"
+	"github.com/thanishsid/dingilink-server/internal/types/apperror"
+)
+
+type PollLoader = *dataloader.Loader[int64, *model.Poll]
+
+func newPollLoader(d db.DBQ) PollLoader {
+	cache := &dataloader.NoCache[int64, *model.Poll]{}
+
+	loader := dataloader.NewBatchedLoader(func(ctx context.Context, ids []int64) []*dataloader.Result[*model.Poll] {
+		results := make([]*dataloader.Result[*model.Poll], len(ids))
+
+		setError := func(err error) []*dataloader.Result[*model.Poll] {
+			for idx := range ids {
+				results[idx] = &dataloader.Result[*model.Poll]{
+					Error: err,
+				}
+			}
+			return results
+		}
+
+		polls, err := d.GetBatchedPolls(ctx, ids)
+		if err != nil {
+			return setError(err)
+		}
+
+		pollIDs := make([]int64, len(polls))
+		for idx, p := range polls {
+			pollIDs[idx] = p.ID
+		}
+
+		options, err := d.GetBatchedPollOptions(ctx, pollIDs)
+		if err != nil {
+			return setError(err)
+		}
+
+		pollMap := make(map[int64]*model.Poll, len(polls))
+		messagePollMap := make(map[int64]*model.Poll, len(polls))
+
+		for _, p := range polls {
+			poll := &model.Poll{
+				ID:             p.ID,
+				MessageID:      p.MessageID,
+				Question:       p.Question,
+				MultipleChoice: p.MultipleChoice,
+				Anonymous:      p.Anonymous,
+				ClosesAt:       p.ClosesAt,
+			}
+
+			pollMap[p.ID] = poll
+			messagePollMap[p.MessageID] = poll
+		}
+
+		for _, o := range options {
+			poll, ok := pollMap[o.PollID]
+			if !ok {
+				continue
+			}
+
+			poll.Options = append(poll.Options, &model.PollOption{
+				ID:        o.ID,
+				Text:      o.OptionText,
+				VoterIDs:  o.VoterIds,
+				Anonymous: poll.Anonymous,
+			})
+		}
+
+		for idx, id := range ids {
+			poll, ok := messagePollMap[id]
+			if !ok {
+				results[idx] = &dataloader.Result[*model.Poll]{
+					Error: apperror.ErrNotFound,
+				}
+				continue
+			}
+
+			results[idx] = &dataloader.Result[*model.Poll]{
+				Data: poll,
+			}
+		}
+
+		return results
+	}, dataloader.WithCache(cache))
+
+	return loader
+}
@@ -0,0 +1,62 @@
+package dtloader
+
+import (
+	"context"
+
+	"github.com/graph-gophers/dataloader/v7"
+
+	"github.com/thanishsid/dingilink-server/internal/db"
+)
+
+// Pair of users checked for a block in either direction.
+type UserPair struct {
+	UserID      int64
+	OtherUserID int64
+}
+
+type UsersBlockedLoader = *dataloader.Loader[UserPair, bool]
+
+func newUsersBlockedLoader(d db.DBQ) UsersBlockedLoader {
+	cache := &dataloader.NoCache[UserPair, bool]{}
+
+	loader := dataloader.NewBatchedLoader(func(ctx context.Context, pairs []UserPair) []*dataloader.Result[bool] {
+		results := make([]*dataloader.Result[bool], len(pairs))
+
+		userIDs := make([]int64, len(pairs))
+		otherUserIDs := make([]int64, len(pairs))
+
+		for idx, p := range pairs {
+			userIDs[idx] = p.UserID
+			otherUserIDs[idx] = p.OtherUserID
+		}
+
+		res, err := d.GetBatchedUsersBlocked(ctx, db.GetBatchedUsersBlockedParams{
+			UserIds:      userIDs,
+			OtherUserIds: otherUserIDs,
+		})
+		if err != nil {
+			for idx := range pairs {
+				results[idx] = &dataloader.Result[bool]{
+					Error: err,
+				}
+			}
+			return results
+		}
+
+		blockedMap := make(map[UserPair]bool, len(res))
+
+		for _, b := range res {
+			blockedMap[UserPair{UserID: b.UserID, OtherUserID: b.OtherUserID}] = true
+		}
+
+		for idx, p := range pairs {
+			results[idx] = &dataloader.Result[bool]{
+				Data: blockedMap[p],
+			}
+		}
+
+		return results
+	}, dataloader.WithCache(cache))
+
+	return loader
+}
@@ -0,0 +1,43 @@
+package model
+
+import "github.com/jackc/pgx/v5/pgtype"
+
+type ChannelRole string
+
+const (
+	ChannelRoleOwner      = "owner"
+	ChannelRoleAdmin      = "admin"
+	ChannelRoleSubscriber = "subscriber"
+)
+
+// A broadcast channel where the owner and admins post and subscribers read and react.
+type Channel struct {
+	ID          int64   `json:"id"`
+	Handle      *string `json:"handle"`
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+	Image       *string `json:"image"`
+	IsPublic    bool    `json:"isPublic"`
+	CreatedBy   int64   `json:"createdBy"`
+
+	// Only exposed to the owner and admins of the channel.
+	InviteCode string `json:"-"`
+}
+
+type ChannelPost struct {
+	ID          int64              `json:"id"`
+	ChannelID   int64              `json:"channelId"`
+	AuthorID    int64              `json:"authorId"`
+	TextContent *string            `json:"textContent"`
+	Media       *string            `json:"media"`
+	PostedAt    pgtype.Timestamptz `json:"postedAt"`
+}
+
+type ChannelPostEdge = Edge[*ChannelPost]
+type ChannelPostConnection Connection[*ChannelPost]
+
+// Number of reactions with an emoji on a channel post.
+type ChannelPostReactionCount struct {
+	Emoji string `json:"emoji"`
+	Count int64  `json:"count"`
+}
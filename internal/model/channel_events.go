@@ -0,0 +1,26 @@
+package model
+
+type ChannelEventType string
+
+const (
+	ChannelEventTypeUpdated          = "updated"
+	ChannelEventTypeDeleted          = "deleted"
+	ChannelEventTypePostCreated      = "post_created"
+	ChannelEventTypePostDeleted      = "post_deleted"
+	ChannelEventTypeReactionsUpdated = "reactions_updated"
+
+	// Internal event for the subscriptions of a user who left the channel, not delivered to clients.
+	ChannelEventTypeMemberLeft = "member_left"
+)
+
+// Published once on the subject of a channel and received by every subscriber listening to the channel.
+type ChannelEvent struct {
+	Type      ChannelEventType `json:"type"`
+	ChannelID int64            `json:"channelId"`
+	Channel   *Channel         `json:"channel,omitempty"`
+	Post      *ChannelPost     `json:"post,omitempty"`
+	PostID    *int64           `json:"postId,omitempty"`
+	UserID    *int64           `json:"userId,omitempty"`
+
+	Reactions []*ChannelPostReactionCount `json:"reactions,omitempty"`
+}
@@ -2,6 +2,16 @@ package model
 
 import (
 	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type ChatType string
+
+const (
+	ChatTypeDirect = "direct"
+	ChatTypeGroup  = "group"
 )
 
 type Chat interface {
@@ -14,10 +24,15 @@ type ChatPreview interface {
 	ID() string
 }
 
+type ChatPreviewEdge = Edge[ChatPreview]
+type ChatPreviewConnection Connection[ChatPreview]
+
 type DirectChatPreview struct {
 	UserID             int64
 	LastMessageID      int64
 	UnreadMessageCount int64
+	Draft              *ChatDraft
+	ChatSettings
 }
 
 func (DirectChatPreview) IsChatPreview() {}
@@ -29,6 +44,8 @@ type GroupChatPreview struct {
 	GroupID            int64
 	LastMessageID      int64
 	UnreadMessageCount int64
+	Draft              *ChatDraft
+	ChatSettings
 }
 
 func (GroupChatPreview) IsChatPreview() {}
@@ -53,3 +70,31 @@ func (GroupChat) IsChat() {}
 func (c GroupChat) ID() string {
 	return fmt.Sprintf("group_%d", c.GroupID)
 }
+
+// Unsent message text of a user in a chat.
+type ChatDraft struct {
+	ChatID            string             `json:"chatId"`
+	Text              string             `json:"text"`
+	ReplyForMessageID *int64             `json:"replyForMessageId"`
+	UpdatedAt         pgtype.Timestamptz `json:"updatedAt"`
+}
+
+// Settings of a chat for a user.
+type ChatSettings struct {
+	ChatID     string
+	ArchivedAt pgtype.Timestamptz
+	PinOrder   *int32
+	MutedUntil pgtype.Timestamptz
+}
+
+func (s ChatSettings) IsArchived() bool {
+	return s.ArchivedAt.Valid
+}
+
+func (s ChatSettings) IsPinned() bool {
+	return s.PinOrder != nil
+}
+
+func (s ChatSettings) IsMuted() bool {
+	return s.MutedUntil.Valid && s.MutedUntil.Time.After(time.Now())
+}
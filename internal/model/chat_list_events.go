@@ -0,0 +1,40 @@
+package model
+
+// Sent when the preview of a chat changes e.g. a new message, unread count, mute or archive state.
+// The event carries the latest preview so subscribers don't have to load the chat,
+// the preview is nil when the chat was removed from the chat list of the user.
+type ChatListEvent struct {
+	ChatID  string              `json:"chatId"`
+	Preview *ChatPreviewPayload `json:"preview"`
+}
+
+// Serializable fields of a direct or group chat preview.
+type ChatPreviewPayload struct {
+	IsGroupChat        bool         `json:"isGroupChat"`
+	TargetID           int64        `json:"targetId"`
+	LastMessageID      int64        `json:"lastMessageId"`
+	UnreadMessageCount int64        `json:"unreadMessageCount"`
+	Draft              *ChatDraft   `json:"draft"`
+	Settings           ChatSettings `json:"settings"`
+}
+
+// Build the direct or group chat preview of the payload.
+func (p ChatPreviewPayload) Build() ChatPreview {
+	if p.IsGroupChat {
+		return GroupChatPreview{
+			GroupID:            p.TargetID,
+			LastMessageID:      p.LastMessageID,
+			UnreadMessageCount: p.UnreadMessageCount,
+			Draft:              p.Draft,
+			ChatSettings:       p.Settings,
+		}
+	}
+
+	return DirectChatPreview{
+		UserID:             p.TargetID,
+		LastMessageID:      p.LastMessageID,
+		UnreadMessageCount: p.UnreadMessageCount,
+		Draft:              p.Draft,
+		ChatSettings:       p.Settings,
+	}
+}
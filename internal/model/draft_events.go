@@ -0,0 +1,14 @@
+package model
+
+type DraftEventType string
+
+const (
+	DraftEventTypeUpdated = "updated"
+	DraftEventTypeCleared = "cleared"
+)
+
+type DraftEvent struct {
+	Type   DraftEventType `json:"type"`
+	ChatID string         `json:"chatId"`
+	Draft  *ChatDraft     `json:"draft"`
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"github.com/thanishsid/dingilink-server/internal/types"
+)
+
+type ChatExportFormat string
+
+const (
+	ChatExportFormatJSON = "JSON"
+	ChatExportFormatHTML = "HTML"
+)
+
+// Details of an exported chat written before the messages.
+type ChatExportInfo struct {
+	ChatID     string    `json:"chatId"`
+	ChatName   string    `json:"chatName"`
+	ExportedAt time.Time `json:"exportedAt"`
+}
+
+// A message of an exported chat.
+type ChatExportMessage struct {
+	ID                int64           `json:"id"`
+	Type              string          `json:"type"`
+	SenderID          int64           `json:"senderId"`
+	SenderName        string          `json:"senderName"`
+	Text              *string         `json:"text,omitempty"`
+	Media             *string         `json:"media,omitempty"`
+	MediaURL          *string         `json:"mediaUrl,omitempty"`
+	Location          *types.LatLng   `json:"location,omitempty"`
+	Poll              *ChatExportPoll `json:"poll,omitempty"`
+	SystemEvent       *SystemEvent    `json:"systemEvent,omitempty"`
+	ReplyForMessageID *int64          `json:"replyForMessageId,omitempty"`
+	SentAt            time.Time       `json:"sentAt"`
+	Deleted           bool            `json:"deleted"`
+}
+
+// The poll of an exported poll message with the number of votes of each option.
+type ChatExportPoll struct {
+	Question       string                 `json:"question"`
+	MultipleChoice bool                   `json:"multipleChoice"`
+	Anonymous      bool                   `json:"anonymous"`
+	ClosesAt       *time.Time             `json:"closesAt,omitempty"`
+	Options        []ChatExportPollOption `json:"options"`
+}
+
+type ChatExportPollOption struct {
+	Text      string `json:"text"`
+	VoteCount int    `json:"voteCount"`
+}
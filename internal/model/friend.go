@@ -0,0 +1,41 @@
+package model
+
+import "github.com/jackc/pgx/v5/pgtype"
+
+type FriendshipStatus string
+
+const (
+	FriendshipStatusPending  = "pending"
+	FriendshipStatusAccepted = "accepted"
+	FriendshipStatusBlocked  = "blocked"
+)
+
+// An accepted friendship seen from one of the two users.
+type Friend struct {
+	FriendshipID int64              `json:"friendshipId"`
+	UserID       int64              `json:"userId"`
+	Since        pgtype.Timestamptz `json:"since"`
+}
+
+type FriendEdge = Edge[*Friend]
+type FriendConnection Connection[*Friend]
+
+// A pending friend request sent by the sender to the recipient.
+type FriendRequest struct {
+	ID          int64              `json:"id"`
+	SenderID    int64              `json:"senderId"`
+	RecipientID int64              `json:"recipientId"`
+	CreatedAt   pgtype.Timestamptz `json:"createdAt"`
+}
+
+type FriendRequestEdge = Edge[*FriendRequest]
+type FriendRequestConnection Connection[*FriendRequest]
+
+// A user blocked by the current user.
+type BlockedUser struct {
+	UserID    int64              `json:"userId"`
+	BlockedAt pgtype.Timestamptz `json:"blockedAt"`
+}
+
+type BlockedUserEdge = Edge[*BlockedUser]
+type BlockedUserConnection Connection[*BlockedUser]
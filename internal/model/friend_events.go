@@ -0,0 +1,23 @@
+package model
+
+type FriendEventType string
+
+const (
+	FriendEventTypeRequestReceived  = "request_received"
+	FriendEventTypeRequestSent      = "request_sent"
+	FriendEventTypeRequestAccepted  = "request_accepted"
+	FriendEventTypeRequestDeclined  = "request_declined"
+	FriendEventTypeRequestCancelled = "request_cancelled"
+	FriendEventTypeUnfriended       = "unfriended"
+	FriendEventTypeBlocked          = "blocked"
+	FriendEventTypeUnblocked        = "unblocked"
+)
+
+// Sent to the devices of a user when the friendships or friend requests of the user change.
+// UserID is the other user of the friendship, block events are only sent to the user who blocked.
+type FriendEvent struct {
+	Type    FriendEventType `json:"type"`
+	UserID  int64           `json:"userId"`
+	Request *FriendRequest  `json:"request,omitempty"`
+	Friend  *Friend         `json:"friend,omitempty"`
+}
@@ -2,12 +2,28 @@ package model
 
 import "github.com/jackc/pgx/v5/pgtype"
 
+type GroupJoinPolicy string
+
+const (
+	GroupJoinPolicyOpen             = "open"
+	GroupJoinPolicyApprovalRequired = "approval_required"
+)
+
 type Group struct {
-	ID          int64
-	Name        string
-	Description *string
-	Image       *string
-	CreatedBy   int64
+	ID          int64           `json:"id"`
+	Name        string          `json:"name"`
+	Description *string         `json:"description"`
+	Image       *string         `json:"image"`
+	CreatedBy   int64           `json:"createdBy"`
+	JoinPolicy  GroupJoinPolicy `json:"joinPolicy"`
+	Policies    GroupPolicies   `json:"policies"`
+}
+
+// Actions of a group restricted to its admins.
+type GroupPolicies struct {
+	OnlyAdminsCanSendMessages bool `json:"onlyAdminsCanSendMessages"`
+	OnlyAdminsCanEditInfo     bool `json:"onlyAdminsCanEditInfo"`
+	OnlyAdminsCanAddMembers   bool `json:"onlyAdminsCanAddMembers"`
 }
 
 type GroupMember struct {
@@ -17,3 +33,60 @@ type GroupMember struct {
 	IsOwner  bool
 	JoinedAt pgtype.Timestamptz
 }
+
+type GroupMemberEdge = Edge[*GroupMember]
+type GroupMemberConnection Connection[*GroupMember]
+
+type GroupInvite struct {
+	ID        int64
+	GroupID   int64
+	Code      string
+	CreatedBy int64
+	MaxUses   *int32
+	UseCount  int32
+	ExpiresAt pgtype.Timestamptz
+	RevokedAt pgtype.Timestamptz
+	CreatedAt pgtype.Timestamptz
+}
+
+// A join to a group through an invite.
+type GroupInviteUse struct {
+	ID     int64
+	UserID int64
+	UsedAt pgtype.Timestamptz
+}
+
+// Public details of a group shown before joining it through an invite.
+type GroupInvitePreview struct {
+	GroupID     int64
+	Name        string
+	Description *string
+	Image       *string
+	MemberCount int64
+	JoinPolicy  GroupJoinPolicy
+}
+
+type GroupJoinRequestStatus string
+
+const (
+	GroupJoinRequestStatusPending  = "pending"
+	GroupJoinRequestStatusApproved = "approved"
+	GroupJoinRequestStatusRejected = "rejected"
+)
+
+// Request of a user to join a group that requires approval.
+type GroupJoinRequest struct {
+	ID        int64                  `json:"id"`
+	GroupID   int64                  `json:"groupId"`
+	UserID    int64                  `json:"userId"`
+	Status    GroupJoinRequestStatus `json:"status"`
+	CreatedAt pgtype.Timestamptz     `json:"createdAt"`
+	DecidedBy *int64                 `json:"decidedBy"`
+	DecidedAt pgtype.Timestamptz     `json:"decidedAt"`
+}
+
+// Result of joining a group through an invite, either the joined group or the pending join request.
+type JoinGroupResult struct {
+	Group       *Group
+	JoinRequest *GroupJoinRequest
+}
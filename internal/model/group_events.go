@@ -0,0 +1,27 @@
+package model
+
+type GroupEventType string
+
+const (
+	GroupEventTypeCreated       = "created"
+	GroupEventTypeUpdated       = "updated"
+	GroupEventTypeDeleted       = "deleted"
+	GroupEventTypeMemberAdded   = "member_added"
+	GroupEventTypeMemberRemoved = "member_removed"
+	GroupEventTypeMemberUpdated = "member_updated"
+
+	GroupEventTypeJoinRequested       = "join_requested"
+	GroupEventTypeJoinRequestApproved = "join_request_approved"
+	GroupEventTypeJoinRequestRejected = "join_request_rejected"
+)
+
+// Sent to the members of a group when the group or its members change,
+// removed members receive the event of their removal. Join request events are sent to the admins and the requester.
+type GroupEvent struct {
+	Type    GroupEventType `json:"type"`
+	GroupID int64          `json:"groupId"`
+	Group   *Group         `json:"group"`
+	UserIDs []int64        `json:"userIds,omitempty"`
+
+	JoinRequest *GroupJoinRequest `json:"joinRequest,omitempty"`
+}
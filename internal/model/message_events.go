@@ -6,11 +6,27 @@ const (
 	MessageEventTypeNew     = "new"
 	MessageEventTypeDeleted = "deleted"
 	MessageEventTypeEdited  = "edited"
+
+	// Sent when the votes of a poll message change.
+	MessageEventTypePollUpdated = "poll_updated"
 )
 
 type MessageEvent struct {
-	Type      MessageEventType `json:"type"`
-	MessageID int64            `json:"messageId"`
+	Type            MessageEventType `json:"type"`
+	MessageID       int64            `json:"messageId"`
+	ClientMessageID *string          `json:"clientMessageId,omitempty"`
+
+	// Chat id of the message as seen by the receiver of the event.
+	ChatID string `json:"chatId"`
+
+	// Position of the event in its chat, increases by one with every event of the chat so clients can detect gaps.
+	Sequence int64 `json:"sequence"`
+
+	// The message at the time of the event, lets subscribers resolve the message without a database round trip.
+	Payload *MessageBuilder `json:"payload,omitempty"`
+
+	// Id of the event in the events log of the receiver, used to resume a subscription after reconnecting.
+	EventID int64 `json:"eventId"`
 }
 
 func (me MessageEvent) ID() int64 {
@@ -2,6 +2,7 @@ package model
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/jackc/pgx/v5/pgtype"
@@ -29,6 +30,8 @@ const (
 	MessageTypeVideo    = "video"
 	MessageTypeDocument = "document"
 	MessageTypeLocation = "location"
+	MessageTypePoll     = "poll"
+	MessageTypeSystem   = "system"
 )
 
 type GenericMessage[T any] struct {
@@ -97,6 +100,31 @@ func (m AudioMessage) GetID() int64 { return m.ID }
 func (m AudioMessage) ChatID(ctx context.Context) (string, error) {
 	return getChatID(ctx, m.SenderID, m.RecipientID, m.GroupID)
 }
+func (m AudioMessage) Duration() (*float64, error) {
+	metadata, err := DecodeObjectMetadata(m.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return metadata.Duration, nil
+}
+func (m AudioMessage) Waveform() ([]int, error) {
+	metadata, err := DecodeObjectMetadata(m.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if metadata.Waveform == nil {
+		return nil, nil
+	}
+
+	waveform := make([]int, len(metadata.Waveform))
+	for idx, amplitude := range metadata.Waveform {
+		waveform[idx] = int(amplitude)
+	}
+
+	return waveform, nil
+}
 
 // Video Message
 type VideoMessage GenericMessage[string]
@@ -133,6 +161,28 @@ func (m LocationMessage) ChatID(ctx context.Context) (string, error) {
 	return getChatID(ctx, m.SenderID, m.RecipientID, m.GroupID)
 }
 
+// Poll Message
+type PollMessage GenericMessage[any]
+
+func (PollMessage) IsMessage()     {}
+func (m PollMessage) GetID() int64 { return m.ID }
+func (m PollMessage) ChatID(ctx context.Context) (string, error) {
+	return getChatID(ctx, m.SenderID, m.RecipientID, m.GroupID)
+}
+
+// System Message
+type SystemMessage GenericMessage[SystemEvent]
+
+func (SystemMessage) IsMessage()     {}
+func (m SystemMessage) GetID() int64 { return m.ID }
+func (m SystemMessage) ChatID(ctx context.Context) (string, error) {
+	return getChatID(ctx, m.SenderID, m.RecipientID, m.GroupID)
+}
+func (m SystemMessage) Kind() SystemEventKind            { return m.Payload.Kind }
+func (m SystemMessage) GroupName() *string               { return m.Payload.GroupName }
+func (m SystemMessage) GroupImage() *string              { return m.Payload.GroupImage }
+func (m SystemMessage) DisappearingTimerSeconds() *int64 { return m.Payload.DisappearingTimerSeconds }
+
 // Deleted Message
 type DeletedMessage GenericMessage[any]
 
@@ -157,6 +207,7 @@ type MessageBuilder struct {
 	SentAt            pgtype.Timestamptz `json:"sentAt"`
 	DeletedAt         pgtype.Timestamptz `json:"deletedAt"`
 	DeletedBy         *int64             `json:"deletedBy"`
+	SystemEvent       []byte             `json:"systemEvent"`
 }
 
 func (m MessageBuilder) Build() (Message, error) {
@@ -233,6 +284,31 @@ func (m MessageBuilder) Build() (Message, error) {
 				Location: m.Location,
 			},
 		}
+	case "poll":
+		msg = PollMessage{
+			ID:          m.ID,
+			SenderID:    m.SenderID,
+			RecipientID: m.RecipientID,
+			GroupID:     m.GroupID,
+			TextContent: m.TextContent,
+			ParentID:    m.ReplyForMessageID,
+			SentAt:      m.SentAt,
+		}
+	case "system":
+		var event SystemEvent
+
+		if err := json.Unmarshal(m.SystemEvent, &event); err != nil {
+			return nil, fmt.Errorf("failed to decode system event: %w", err)
+		}
+
+		msg = SystemMessage{
+			ID:          m.ID,
+			SenderID:    m.SenderID,
+			RecipientID: m.RecipientID,
+			GroupID:     m.GroupID,
+			SentAt:      m.SentAt,
+			Payload:     event,
+		}
 	default:
 		return nil, fmt.Errorf("invalid message type")
 	}
@@ -6,6 +6,7 @@ type Connection[T any] struct {
 }
 
 type PageInfo struct {
+	StartCursor     *string
 	EndCursor       *string
 	HasNextPage     bool
 	HasPreviousPage bool
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"gopkg.in/typ.v4/slices"
+
+	"github.com/thanishsid/dingilink-server/internal/pkg/security"
+)
+
+type Poll struct {
+	ID             int64
+	MessageID      int64
+	Question       string
+	MultipleChoice bool
+	Anonymous      bool
+	ClosesAt       pgtype.Timestamptz
+	Options        []*PollOption
+}
+
+// Check if the poll no longer accepts votes.
+func (p Poll) Closed() bool {
+	return p.ClosesAt.Valid && !p.ClosesAt.Time.After(time.Now())
+}
+
+// Get the number of users who have voted in the poll.
+func (p Poll) TotalVoters() int {
+	voters := make(map[int64]struct{})
+
+	for _, o := range p.Options {
+		for _, id := range o.VoterIDs {
+			voters[id] = struct{}{}
+		}
+	}
+
+	return len(voters)
+}
+
+type PollOption struct {
+	ID        int64
+	Text      string
+	VoterIDs  []int64
+	Anonymous bool
+}
+
+func (o PollOption) VoteCount() int {
+	return len(o.VoterIDs)
+}
+
+// Check if the current user has voted for the option.
+func (o PollOption) VotedByMe(ctx context.Context) (bool, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return false, err
+	}
+
+	return slices.Contains(o.VoterIDs, userInfo.User.ID), nil
+}
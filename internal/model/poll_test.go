@@ -0,0 +1,49 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestPollClosed(t *testing.T) {
+	tests := []struct {
+		name     string
+		closesAt pgtype.Timestamptz
+		want     bool
+	}{
+		{"no close time", pgtype.Timestamptz{}, false},
+		{"closes later", pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true}, false},
+		{"closed", pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (Poll{ClosesAt: tt.closesAt}).Closed(); got != tt.want {
+				t.Errorf("Closed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPollTotalVoters(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []*PollOption
+		want    int
+	}{
+		{"no options", nil, 0},
+		{"no votes", []*PollOption{{ID: 1}, {ID: 2}}, 0},
+		{"single choice", []*PollOption{{ID: 1, VoterIDs: []int64{1, 2}}, {ID: 2, VoterIDs: []int64{3}}}, 3},
+		{"multiple choice counts each voter once", []*PollOption{{ID: 1, VoterIDs: []int64{1, 2}}, {ID: 2, VoterIDs: []int64{1, 2, 3}}}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (Poll{Options: tt.options}).TotalVoters(); got != tt.want {
+				t.Errorf("TotalVoters() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
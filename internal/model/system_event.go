@@ -0,0 +1,25 @@
+package model
+
+type SystemEventKind string
+
+const (
+	SystemEventKindGroupCreated             = "group_created"
+	SystemEventKindMemberAdded              = "member_added"
+	SystemEventKindMemberRemoved            = "member_removed"
+	SystemEventKindMemberLeft               = "member_left"
+	SystemEventKindMemberJoined             = "member_joined"
+	SystemEventKindGroupRenamed             = "group_renamed"
+	SystemEventKindGroupIconChanged         = "group_icon_changed"
+	SystemEventKindDisappearingTimerChanged = "disappearing_timer_changed"
+	SystemEventKindCallMissed               = "call_missed"
+)
+
+// Structured event of a system message, the actor of the event is the sender of the message.
+type SystemEvent struct {
+	Kind                     SystemEventKind `json:"kind"`
+	TargetUserIDs            []int64         `json:"targetUserIds,omitempty"`
+	GroupName                *string         `json:"groupName,omitempty"`
+	GroupImage               *string         `json:"groupImage,omitempty"`
+	DisappearingTimerSeconds *int64          `json:"disappearingTimerSeconds,omitempty"`
+	CallID                   *int64          `json:"callId,omitempty"`
+}
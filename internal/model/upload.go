@@ -14,6 +14,7 @@ type ObjectMetadata struct {
 	Size        int64     `json:"size"`
 	Duration    *float64  `json:"duration,omitempty"`
 	Thumbnail   *string   `json:"thumbnail,omitempty"`
+	Waveform    []byte    `json:"waveform,omitempty"`
 }
 
 func (o ObjectMetadata) GenerateObjectKey() (string, error) {
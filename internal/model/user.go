@@ -11,6 +11,25 @@ type User struct {
 	FriendCount int64
 }
 
+type UserEdge = Edge[*User]
+type UserConnection Connection[*User]
+
+type NearbyDistance string
+
+const (
+	NearbyDistanceWithin1Km  = "within_1km"
+	NearbyDistanceWithin5Km  = "within_5km"
+	NearbyDistanceWithin10Km = "within_10km"
+	NearbyDistanceWithin25Km = "within_25km"
+	NearbyDistanceWithin50Km = "within_50km"
+)
+
+// A user sharing their location near the current user, the distance is bucketed so the exact location is not revealed.
+type NearbyUser struct {
+	User     *User
+	Distance NearbyDistance
+}
+
 type Role struct {
 	ID          int64
 	Name        string
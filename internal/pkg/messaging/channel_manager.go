@@ -5,6 +5,7 @@ import (
 	"log"
 	"sync"
 
+	"github.com/google/uuid"
 	"github.com/nats-io/nats.go"
 )
 
@@ -13,9 +14,10 @@ type Client[V any] struct {
 	id      string
 	sub     *nats.Subscription
 	channel chan V
+	done    chan struct{}
 }
 
-// ChannelManager holds the map of user ID to channels for subscriptions
+// ChannelManager holds the map of client ID to channels for subscriptions
 type ChannelManager[V any] struct {
 	natsConn *nats.Conn
 	clients  map[string]*Client[V]
@@ -34,12 +36,14 @@ func NewChannelManager[V any](natsURL string) (*ChannelManager[V], error) {
 	}, nil
 }
 
-// Subscribe adds a value to the channel map
-func (m *ChannelManager[V]) Subscribe(id string) (chan V, error) {
+// Subscribe to a subject, a subject can have many clients (e.g. one per device of a user).
+// Returns the client id used to unsubscribe and the payload channel.
+func (m *ChannelManager[V]) Subscribe(subject string) (string, chan V, error) {
 	ch := make(chan V)
+	done := make(chan struct{})
 
-	// Subscribe to NATS for this user's subject
-	sub, err := m.natsConn.Subscribe(id, func(msg *nats.Msg) {
+	// Subscribe to NATS for this subject
+	sub, err := m.natsConn.Subscribe(subject, func(msg *nats.Msg) {
 		var payload V
 
 		if err := json.Unmarshal(msg.Data, &payload); err != nil {
@@ -47,61 +51,51 @@ func (m *ChannelManager[V]) Subscribe(id string) (chan V, error) {
 			return
 		}
 
-		ch <- payload
+		select {
+		case ch <- payload:
+		case <-done:
+		}
 	})
 	if err != nil {
-		return nil, err
+		return "", nil, err
 	}
 
 	client := Client[V]{
-		id:      id,
+		id:      uuid.NewString(),
 		sub:     sub,
 		channel: ch,
+		done:    done,
 	}
 
 	m.mu.Lock()
-	m.clients[id] = &client
+	m.clients[client.id] = &client
 	m.mu.Unlock()
 
-	return ch, nil
+	return client.id, ch, nil
 }
 
 // Unsubscribe a client
-func (m *ChannelManager[V]) Unsubscribe(id string) error {
-	c, exists := m.clients[id]
+func (m *ChannelManager[V]) Unsubscribe(clientID string) error {
+	m.mu.Lock()
+	c, exists := m.clients[clientID]
+	delete(m.clients, clientID)
+	m.mu.Unlock()
+
 	if !exists {
 		return nil
 	}
 
-	if err := c.sub.Unsubscribe(); err != nil {
-		return err
-	}
+	close(c.done)
 
-	m.mu.Lock()
-	delete(m.clients, id)
-	m.mu.Unlock()
-
-	return nil
+	return c.sub.Unsubscribe()
 }
 
-// Send an payload to the id and subject.
-func (m *ChannelManager[V]) SendPayload(id string, payload V) error {
-	m.mu.RLock() // Lock before accessing the channels map
-	defer m.mu.RUnlock()
-
-	client, exists := m.clients[id]
-	if exists {
-		client.channel <- payload
-	} else {
-		payloadJson, err := json.Marshal(payload)
-		if err != nil {
-			return err
-		}
-
-		if err := m.natsConn.Publish(id, payloadJson); err != nil {
-			return err
-		}
+// Send a payload to all clients subscribed to the subject.
+func (m *ChannelManager[V]) SendPayload(subject string, payload V) error {
+	payloadJson, err := json.Marshal(payload)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	return m.natsConn.Publish(subject, payloadJson)
 }
@@ -0,0 +1,184 @@
+package messaging
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+type testPayload struct {
+	Text string `json:"text"`
+}
+
+func newTestChannelManager(t *testing.T) *ChannelManager[testPayload] {
+	t.Helper()
+
+	ns, err := server.NewServer(&server.Options{
+		Host:   "127.0.0.1",
+		Port:   server.RANDOM_PORT,
+		NoLog:  true,
+		NoSigs: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create nats server: %v", err)
+	}
+
+	go ns.Start()
+	t.Cleanup(ns.Shutdown)
+
+	if !ns.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server is not ready for connections")
+	}
+
+	m, err := NewChannelManager[testPayload](ns.ClientURL())
+	if err != nil {
+		t.Fatalf("NewChannelManager() error = %v", err)
+	}
+	t.Cleanup(m.natsConn.Close)
+
+	return m
+}
+
+func subscribe(t *testing.T, m *ChannelManager[testPayload], subject string) (string, chan testPayload) {
+	t.Helper()
+
+	clientID, ch, err := m.Subscribe(subject)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	// Make sure the subscription reached the server before anything is published.
+	if err := m.natsConn.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	return clientID, ch
+}
+
+func send(t *testing.T, m *ChannelManager[testPayload], subject string, payload testPayload) {
+	t.Helper()
+
+	if err := m.SendPayload(subject, payload); err != nil {
+		t.Fatalf("SendPayload() error = %v", err)
+	}
+}
+
+func receive(t *testing.T, ch chan testPayload) testPayload {
+	t.Helper()
+
+	select {
+	case p := <-ch:
+		return p
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a payload")
+	}
+
+	return testPayload{}
+}
+
+func TestChannelManagerClients(t *testing.T) {
+	tests := []struct {
+		name        string
+		unsubscribe bool
+	}{
+		{"two clients on one subject", false},
+		{"unsubscribe one of two clients", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestChannelManager(t)
+
+			firstID, first := subscribe(t, m, "user_1")
+			_, second := subscribe(t, m, "user_1")
+			_, other := subscribe(t, m, "user_2")
+
+			if tt.unsubscribe {
+				if err := m.Unsubscribe(firstID); err != nil {
+					t.Fatalf("Unsubscribe() error = %v", err)
+				}
+			}
+
+			send(t, m, "user_1", testPayload{Text: "hello"})
+
+			if got := receive(t, second); got.Text != "hello" {
+				t.Errorf("second client got %q, want %q", got.Text, "hello")
+			}
+
+			if !tt.unsubscribe {
+				if got := receive(t, first); got.Text != "hello" {
+					t.Errorf("first client got %q, want %q", got.Text, "hello")
+				}
+			}
+
+			select {
+			case p := <-first:
+				t.Errorf("unsubscribed client got %q", p.Text)
+			case p := <-other:
+				t.Errorf("client of another subject got %q", p.Text)
+			case <-time.After(100 * time.Millisecond):
+			}
+		})
+	}
+}
+
+func TestChannelManagerUnsubscribeReleasesBlockedSend(t *testing.T) {
+	m := newTestChannelManager(t)
+
+	clientID, ch := subscribe(t, m, "user_1")
+
+	m.mu.RLock()
+	sub := m.clients[clientID].sub
+	m.mu.RUnlock()
+
+	// Nobody reads the channel so the delivery blocks until the client unsubscribes.
+	send(t, m, "user_1", testPayload{Text: "hello"})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		delivered, err := sub.Delivered()
+		if err != nil {
+			t.Fatalf("Delivered() error = %v", err)
+		}
+
+		if delivered == 1 {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the delivery")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	unsubscribed := make(chan error, 1)
+	go func() {
+		unsubscribed <- m.Unsubscribe(clientID)
+	}()
+
+	select {
+	case err := <-unsubscribed:
+		if err != nil {
+			t.Fatalf("Unsubscribe() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Unsubscribe() blocked on the pending delivery")
+	}
+
+	// The blocked delivery is dropped instead of waiting for a reader.
+	select {
+	case p := <-ch:
+		t.Errorf("got %q after unsubscribing", p.Text)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Other clients of the subject keep working.
+	_, other := subscribe(t, m, "user_1")
+	send(t, m, "user_1", testPayload{Text: "again"})
+
+	if got := receive(t, other); got.Text != "again" {
+		t.Errorf("other client got %q, want %q", got.Text, "again")
+	}
+}
@@ -46,3 +46,42 @@ func TestSerializeAndDeserializePayload(t *testing.T) {
 
 	t.Logf("the original type is %T and the result type is: %T", me, p)
 }
+
+func TestSerializeAndDeserializeChatListEventPayload(t *testing.T) {
+	RegisterType[model.ChatListEvent]()
+
+	e := model.ChatListEvent{
+		ChatID: "group_4",
+		Preview: &model.ChatPreviewPayload{
+			IsGroupChat:        true,
+			TargetID:           4,
+			LastMessageID:      20,
+			UnreadMessageCount: 3,
+			Settings: model.ChatSettings{
+				ChatID: "group_4",
+				MutedUntil: pgtype.Timestamptz{
+					Time:  time.Now().UTC().Truncate(time.Microsecond),
+					Valid: true,
+				},
+			},
+		},
+	}
+
+	b, err := SerializePayload(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := DeserializePayload(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(e, p) {
+		t.Errorf("deserialized payload %+v is not equal to %+v", p, e)
+	}
+
+	if _, ok := p.(model.ChatListEvent).Preview.Build().(model.GroupChatPreview); !ok {
+		t.Errorf("preview of a group chat did not build a group chat preview")
+	}
+}
@@ -0,0 +1,941 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/thanishsid/dingilink-server/internal/db"
+	"github.com/thanishsid/dingilink-server/internal/model"
+	"github.com/thanishsid/dingilink-server/internal/pkg/messaging"
+	"github.com/thanishsid/dingilink-server/internal/pkg/security"
+	"github.com/thanishsid/dingilink-server/internal/types/apperror"
+)
+
+const (
+	defaultChannelPostsPageSize int64 = 20
+	maxChannelPostsPageSize     int64 = 100
+	maxChannelSearchResults     int64 = 50
+)
+
+var channelHandleRegex = regexp.MustCompile(`^[a-z0-9_]{4,32}$`)
+
+type ChannelService struct {
+	DB db.DBQ
+	CH *messaging.ChannelManager[*model.ChannelEvent]
+}
+
+// Events of a channel are published once on the subject of the channel instead of once per subscriber.
+func getChannelEventsChannelID(channelID int64) string {
+	return fmt.Sprintf("channel_%d.events", channelID)
+}
+
+// Convert a channel row into a channel model.
+func newChannel(c db.Channel) *model.Channel {
+	return &model.Channel{
+		ID:          c.ID,
+		Handle:      c.Handle,
+		Name:        c.Name,
+		Description: c.Description,
+		Image:       c.Image,
+		IsPublic:    c.IsPublic,
+		CreatedBy:   c.CreatedBy,
+		InviteCode:  c.InviteCode,
+	}
+}
+
+// Convert a channel post row into a channel post model.
+func newChannelPost(p db.ChannelPost) *model.ChannelPost {
+	return &model.ChannelPost{
+		ID:          p.ID,
+		ChannelID:   p.ChannelID,
+		AuthorID:    p.AuthorID,
+		TextContent: p.TextContent,
+		Media:       p.Media,
+		PostedAt:    p.PostedAt,
+	}
+}
+
+type CreateChannelInput struct {
+	Name        string  `json:"name"`
+	Handle      *string `json:"handle"`
+	Description *string `json:"description"`
+	Image       *string `json:"image"`
+	IsPublic    bool    `json:"isPublic"`
+}
+
+func (i CreateChannelInput) Validate() error {
+	return vd.ValidateStruct(&i,
+		vd.Field(&i.Name,
+			vd.Required.Error(apperror.INPUT_REQUIRED),
+			vd.RuneLength(1, 100).Error(apperror.INPUT_TOO_HIGH),
+		),
+		vd.Field(&i.Handle,
+			vd.Required.When(i.IsPublic).Error(apperror.INPUT_REQUIRED),
+			vd.Match(channelHandleRegex).Error(apperror.INPUT_INVALID),
+		),
+		vd.Field(&i.Description, vd.RuneLength(0, 500).Error(apperror.INPUT_TOO_HIGH)),
+	)
+}
+
+// Create a channel, the creator becomes the owner of the channel.
+// Public channels require a unique handle, private channels can only be joined through the invite link.
+func (s *ChannelService) CreateChannel(ctx context.Context, input CreateChannelInput) (*model.Channel, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	handle, err := s.checkChannelHandle(ctx, input.IsPublic, input.Handle, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	inviteCode, err := generateCode()
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	c, err := tx.InsertChannel(ctx, db.InsertChannelParams{
+		Handle:      handle,
+		Name:        input.Name,
+		Description: input.Description,
+		Image:       input.Image,
+		IsPublic:    input.IsPublic,
+		InviteCode:  inviteCode,
+		CreatedBy:   userInfo.User.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.InsertChannelMember(ctx, db.InsertChannelMemberParams{
+		ChannelID: c.ID,
+		UserID:    userInfo.User.ID,
+		Role:      model.ChannelRoleOwner,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return newChannel(c), nil
+}
+
+type UpdateChannelInput struct {
+	ChannelID   int64   `json:"channelId"`
+	Name        string  `json:"name"`
+	Handle      *string `json:"handle"`
+	Description *string `json:"description"`
+	Image       *string `json:"image"`
+	IsPublic    bool    `json:"isPublic"`
+}
+
+func (i UpdateChannelInput) Validate() error {
+	return vd.ValidateStruct(&i,
+		vd.Field(&i.ChannelID, vd.Required.Error(apperror.INPUT_REQUIRED)),
+		vd.Field(&i.Name,
+			vd.Required.Error(apperror.INPUT_REQUIRED),
+			vd.RuneLength(1, 100).Error(apperror.INPUT_TOO_HIGH),
+		),
+		vd.Field(&i.Handle,
+			vd.Required.When(i.IsPublic).Error(apperror.INPUT_REQUIRED),
+			vd.Match(channelHandleRegex).Error(apperror.INPUT_INVALID),
+		),
+		vd.Field(&i.Description, vd.RuneLength(0, 500).Error(apperror.INPUT_TOO_HIGH)),
+	)
+}
+
+// Update the info and visibility of a channel, only the owner and admins can update a channel.
+// The handle of a channel is removed when it is made private.
+func (s *ChannelService) UpdateChannel(ctx context.Context, input UpdateChannelInput) (*model.Channel, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.DB.GetChannelByID(ctx, input.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := authorizeChannelMember(ctx, s.DB, userInfo.User.ID, existing.ID, true); err != nil {
+		return nil, err
+	}
+
+	handle, err := s.checkChannelHandle(ctx, input.IsPublic, input.Handle, existing.Handle)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := s.DB.UpdateChannel(ctx, db.UpdateChannelParams{
+		Handle:      handle,
+		Name:        input.Name,
+		Description: input.Description,
+		Image:       input.Image,
+		IsPublic:    input.IsPublic,
+		ChannelID:   existing.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	channel := newChannel(c)
+
+	go s.sendChannelEvent(&model.ChannelEvent{
+		Type:      model.ChannelEventTypeUpdated,
+		ChannelID: c.ID,
+		Channel:   channel,
+	})
+
+	return channel, nil
+}
+
+// Delete a channel along with its posts, only the owner can delete a channel.
+func (s *ChannelService) DeleteChannel(ctx context.Context, channelID string) error {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return err
+	}
+
+	c, err := s.DB.GetChannelByID(ctx, parseID(channelID))
+	if err != nil {
+		return err
+	}
+
+	if c.CreatedBy != userInfo.User.ID {
+		return apperror.ErrForbidden
+	}
+
+	if err := s.DB.DeleteChannel(ctx, c.ID); err != nil {
+		return err
+	}
+
+	go s.sendChannelEvent(&model.ChannelEvent{
+		Type:      model.ChannelEventTypeDeleted,
+		ChannelID: c.ID,
+		Channel:   newChannel(c),
+	})
+
+	return nil
+}
+
+// Get a channel, public channels are visible to everyone and private channels only to their members.
+func (s *ChannelService) GetChannel(ctx context.Context, channelID string) (*model.Channel, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := s.DB.GetChannelByID(ctx, parseID(channelID))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeChannelRead(ctx, s.DB, userInfo.User.ID, c); err != nil {
+		return nil, err
+	}
+
+	return newChannel(c), nil
+}
+
+// Get a public channel by its handle.
+func (s *ChannelService) GetChannelByHandle(ctx context.Context, handle string) (*model.Channel, error) {
+	if _, err := security.Authorize(ctx, security.User); err != nil {
+		return nil, err
+	}
+
+	c, err := s.DB.GetChannelByHandle(ctx, strings.ToLower(handle))
+	if err != nil {
+		return nil, err
+	}
+
+	return newChannel(c), nil
+}
+
+// Search public channels by the start of their handle or by their name.
+func (s *ChannelService) SearchChannels(ctx context.Context, search string) ([]*model.Channel, error) {
+	if _, err := security.Authorize(ctx, security.User); err != nil {
+		return nil, err
+	}
+
+	likeSearch := newLikeSearch(&search)
+	if likeSearch == nil {
+		return []*model.Channel{}, nil
+	}
+
+	channels, err := s.DB.SearchPublicChannels(ctx, db.SearchPublicChannelsParams{
+		Search:     *likeSearch,
+		MaxResults: maxChannelSearchResults,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.Channel, len(channels))
+	for i, c := range channels {
+		result[i] = newChannel(c)
+	}
+
+	return result, nil
+}
+
+// Get the channels the user owns, administers or is subscribed to.
+func (s *ChannelService) GetMyChannels(ctx context.Context) ([]*model.Channel, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	channels, err := s.DB.GetUserChannels(ctx, userInfo.User.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.Channel, len(channels))
+	for i, c := range channels {
+		result[i] = newChannel(c)
+	}
+
+	return result, nil
+}
+
+// Get the role of the user in a channel, returns nil when the user is not a member of the channel.
+func (s *ChannelService) GetMyChannelRole(ctx context.Context, channelID int64) (*model.ChannelRole, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.DB.GetChannelMember(ctx, db.GetChannelMemberParams{
+		ChannelID: channelID,
+		UserID:    userInfo.User.ID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	role := model.ChannelRole(member.Role)
+
+	return &role, nil
+}
+
+// Get the invite code of a channel, only the owner and admins can view the invite code.
+func (s *ChannelService) GetChannelInviteCode(ctx context.Context, channel *model.Channel) (*string, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.DB.GetChannelMember(ctx, db.GetChannelMemberParams{
+		ChannelID: channel.ID,
+		UserID:    userInfo.User.ID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if member.Role == model.ChannelRoleSubscriber {
+		return nil, nil
+	}
+
+	return &channel.InviteCode, nil
+}
+
+// Subscribe to a public channel.
+func (s *ChannelService) JoinChannel(ctx context.Context, channelID string) (*model.Channel, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := s.DB.GetChannelByID(ctx, parseID(channelID))
+	if err != nil {
+		return nil, err
+	}
+
+	if !c.IsPublic {
+		return nil, apperror.ErrForbidden
+	}
+
+	if _, err := s.DB.InsertChannelMember(ctx, db.InsertChannelMemberParams{
+		ChannelID: c.ID,
+		UserID:    userInfo.User.ID,
+		Role:      model.ChannelRoleSubscriber,
+	}); err != nil {
+		return nil, err
+	}
+
+	return newChannel(c), nil
+}
+
+// Subscribe to a public or private channel through its invite link.
+func (s *ChannelService) JoinChannelByInvite(ctx context.Context, code string) (*model.Channel, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := s.DB.GetChannelByInviteCode(ctx, code)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperror.ErrInvalidChannelInvite
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.DB.InsertChannelMember(ctx, db.InsertChannelMemberParams{
+		ChannelID: c.ID,
+		UserID:    userInfo.User.ID,
+		Role:      model.ChannelRoleSubscriber,
+	}); err != nil {
+		return nil, err
+	}
+
+	return newChannel(c), nil
+}
+
+// Replace the invite link of a channel so the previous link stops working, only the owner and admins can reset the invite link.
+func (s *ChannelService) ResetChannelInvite(ctx context.Context, channelID string) (*model.Channel, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	id := parseID(channelID)
+
+	if _, err := authorizeChannelMember(ctx, s.DB, userInfo.User.ID, id, true); err != nil {
+		return nil, err
+	}
+
+	inviteCode, err := generateCode()
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := s.DB.UpdateChannelInviteCode(ctx, db.UpdateChannelInviteCodeParams{
+		InviteCode: inviteCode,
+		ChannelID:  id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newChannel(c), nil
+}
+
+// Leave a channel, the owner can not leave a channel and must delete it instead.
+func (s *ChannelService) LeaveChannel(ctx context.Context, channelID string) error {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return err
+	}
+
+	id := parseID(channelID)
+
+	member, err := authorizeChannelMember(ctx, s.DB, userInfo.User.ID, id, false)
+	if err != nil {
+		return err
+	}
+
+	if member.Role == model.ChannelRoleOwner {
+		return apperror.ErrChannelOwnerCannotLeave
+	}
+
+	if _, err := s.DB.DeleteChannelMember(ctx, db.DeleteChannelMemberParams{
+		ChannelID: id,
+		UserID:    userInfo.User.ID,
+	}); err != nil {
+		return err
+	}
+
+	// Lets the open subscriptions of the user on every server check if they can still read the channel.
+	go s.sendChannelEvent(&model.ChannelEvent{
+		Type:      model.ChannelEventTypeMemberLeft,
+		ChannelID: id,
+		UserID:    &userInfo.User.ID,
+	})
+
+	return nil
+}
+
+type SetChannelAdminInput struct {
+	ChannelID int64 `json:"channelId"`
+	UserID    int64 `json:"userId"`
+	IsAdmin   bool  `json:"isAdmin"`
+}
+
+func (i SetChannelAdminInput) Validate() error {
+	return vd.ValidateStruct(&i,
+		vd.Field(&i.ChannelID, vd.Required.Error(apperror.INPUT_REQUIRED)),
+		vd.Field(&i.UserID, vd.Required.Error(apperror.INPUT_REQUIRED)),
+	)
+}
+
+// Promote a subscriber to admin or demote an admin to subscriber, only the owner can manage admins.
+func (s *ChannelService) SetChannelAdmin(ctx context.Context, input SetChannelAdminInput) error {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return err
+	}
+
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	c, err := s.DB.GetChannelByID(ctx, input.ChannelID)
+	if err != nil {
+		return err
+	}
+
+	if c.CreatedBy != userInfo.User.ID {
+		return apperror.ErrForbidden
+	}
+
+	role := model.ChannelRoleSubscriber
+	if input.IsAdmin {
+		role = model.ChannelRoleAdmin
+	}
+
+	// The owner row is excluded by the query so the owner can not demote themselves.
+	updated, err := s.DB.SetChannelMemberRole(ctx, db.SetChannelMemberRoleParams{
+		Role:      role,
+		ChannelID: c.ID,
+		UserID:    input.UserID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		return vd.Errors{"userId": errors.New(apperror.INPUT_INVALID)}
+	}
+
+	return nil
+}
+
+type CreateChannelPostInput struct {
+	ChannelID   int64   `json:"channelId"`
+	TextContent *string `json:"textContent"`
+	Media       *string `json:"media"`
+}
+
+func (i CreateChannelPostInput) Validate() error {
+	return vd.ValidateStruct(&i,
+		vd.Field(&i.ChannelID, vd.Required.Error(apperror.INPUT_REQUIRED)),
+		vd.Field(&i.TextContent,
+			vd.Required.When(vd.IsEmpty(i.Media)).Error(apperror.INPUT_REQUIRED),
+			vd.RuneLength(0, 4096).Error(apperror.INPUT_TOO_HIGH),
+		),
+	)
+}
+
+// Publish a post to a channel, only the owner and admins can post.
+func (s *ChannelService) CreateChannelPost(ctx context.Context, input CreateChannelPostInput) (*model.ChannelPost, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := authorizeChannelMember(ctx, s.DB, userInfo.User.ID, input.ChannelID, true); err != nil {
+		return nil, err
+	}
+
+	p, err := s.DB.InsertChannelPost(ctx, db.InsertChannelPostParams{
+		ChannelID:   input.ChannelID,
+		AuthorID:    userInfo.User.ID,
+		TextContent: input.TextContent,
+		Media:       input.Media,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	post := newChannelPost(p)
+
+	go s.sendChannelEvent(&model.ChannelEvent{
+		Type:      model.ChannelEventTypePostCreated,
+		ChannelID: p.ChannelID,
+		Post:      post,
+	})
+
+	return post, nil
+}
+
+// Delete a post of a channel, only the owner and admins can delete posts.
+func (s *ChannelService) DeleteChannelPost(ctx context.Context, postID string) error {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return err
+	}
+
+	p, err := s.DB.GetChannelPostByID(ctx, parseID(postID))
+	if err != nil {
+		return err
+	}
+
+	if _, err := authorizeChannelMember(ctx, s.DB, userInfo.User.ID, p.ChannelID, true); err != nil {
+		return err
+	}
+
+	if _, err := s.DB.DeleteChannelPost(ctx, p.ID); err != nil {
+		return err
+	}
+
+	go s.sendChannelEvent(&model.ChannelEvent{
+		Type:      model.ChannelEventTypePostDeleted,
+		ChannelID: p.ChannelID,
+		PostID:    &p.ID,
+	})
+
+	return nil
+}
+
+type GetChannelPostsInput struct {
+	First *int64  `json:"first"`
+	After *string `json:"after"`
+}
+
+func (i GetChannelPostsInput) Validate() error {
+	return vd.ValidateStruct(&i,
+		vd.Field(&i.First,
+			vd.Min(int64(1)).Error(apperror.INPUT_TOO_LOW),
+			vd.Max(maxChannelPostsPageSize).Error(apperror.INPUT_TOO_HIGH),
+		),
+	)
+}
+
+// Get the posts of a channel from the newest to the oldest.
+func (s *ChannelService) GetChannelPosts(ctx context.Context, channel *model.Channel, input GetChannelPostsInput) (*model.ChannelPostConnection, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	if !channel.IsPublic {
+		if _, err := authorizeChannelMember(ctx, s.DB, userInfo.User.ID, channel.ID, false); err != nil {
+			return nil, err
+		}
+	}
+
+	params := db.GetChannelPostsParams{
+		ChannelID:   channel.ID,
+		ResultLimit: defaultChannelPostsPageSize,
+	}
+
+	if input.First != nil {
+		params.ResultLimit = *input.First
+	}
+
+	params.Cursor, err = parseCursor(input.After)
+	if err != nil {
+		return nil, vd.Errors{"after": errors.New(apperror.INPUT_INVALID)}
+	}
+
+	limit := params.ResultLimit
+
+	// One extra row is requested to find out if there are more posts.
+	params.ResultLimit++
+
+	posts, err := s.DB.GetChannelPosts(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	hasNextPage := int64(len(posts)) > limit
+	if hasNextPage {
+		posts = posts[:limit]
+	}
+
+	edges := make([]model.ChannelPostEdge, len(posts))
+
+	for idx, p := range posts {
+		edges[idx] = model.ChannelPostEdge{
+			Node:   newChannelPost(p),
+			Cursor: fmt.Sprintf("%d", p.ID),
+		}
+	}
+
+	connection := model.ChannelPostConnection{
+		Edges: edges,
+		PageInfo: model.PageInfo{
+			HasNextPage:     hasNextPage,
+			HasPreviousPage: input.After != nil,
+		},
+	}
+
+	if len(edges) > 0 {
+		connection.PageInfo.StartCursor = &edges[0].Cursor
+		connection.PageInfo.EndCursor = &edges[len(edges)-1].Cursor
+	}
+
+	return &connection, nil
+}
+
+// React to a channel post, a user has a single reaction per post which is replaced by a new reaction.
+func (s *ChannelService) ReactToChannelPost(ctx context.Context, postID string, emoji string) error {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return err
+	}
+
+	if err := vd.Validate(emoji,
+		vd.Required.Error(apperror.INPUT_REQUIRED),
+		vd.RuneLength(1, 16).Error(apperror.INPUT_TOO_HIGH),
+	); err != nil {
+		return vd.Errors{"emoji": err}
+	}
+
+	p, err := s.DB.GetChannelPostByID(ctx, parseID(postID))
+	if err != nil {
+		return err
+	}
+
+	if _, err := authorizeChannelMember(ctx, s.DB, userInfo.User.ID, p.ChannelID, false); err != nil {
+		return err
+	}
+
+	if err := s.DB.UpsertChannelPostReaction(ctx, db.UpsertChannelPostReactionParams{
+		PostID: p.ID,
+		UserID: userInfo.User.ID,
+		Emoji:  emoji,
+	}); err != nil {
+		return err
+	}
+
+	return s.sendReactionsUpdated(ctx, p)
+}
+
+// Remove the reaction of the user from a channel post.
+func (s *ChannelService) RemoveChannelPostReaction(ctx context.Context, postID string) error {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return err
+	}
+
+	p, err := s.DB.GetChannelPostByID(ctx, parseID(postID))
+	if err != nil {
+		return err
+	}
+
+	removed, err := s.DB.DeleteChannelPostReaction(ctx, db.DeleteChannelPostReactionParams{
+		PostID: p.ID,
+		UserID: userInfo.User.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if removed == 0 {
+		return nil
+	}
+
+	return s.sendReactionsUpdated(ctx, p)
+}
+
+// Subscribe to the posts and changes of a channel, any user who can view the channel can subscribe.
+func (s *ChannelService) SubscribeToChannelEvents(ctx context.Context, channelID string) (<-chan *model.ChannelEvent, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := s.DB.GetChannelByID(ctx, parseID(channelID))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeChannelRead(ctx, s.DB, userInfo.User.ID, c); err != nil {
+		return nil, err
+	}
+
+	clientID, ch, err := s.CH.Subscribe(getChannelEventsChannelID(c.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		<-ctx.Done()
+		s.CH.Unsubscribe(clientID)
+	}()
+
+	events := make(chan *model.ChannelEvent)
+
+	// Access is checked again when the user leaves or the channel is updated e.g. made private,
+	// the subscription ends when the user can no longer read the channel or the channel is deleted.
+	go func() {
+		defer close(events)
+
+		for {
+			select {
+			case event, ok := <-ch:
+				if !ok {
+					return
+				}
+
+				switch event.Type {
+				case model.ChannelEventTypeMemberLeft:
+					if event.UserID == nil || *event.UserID != userInfo.User.ID {
+						continue
+					}
+
+					if !s.canReadChannel(ctx, userInfo.User.ID, c.ID) {
+						return
+					}
+
+					continue
+				case model.ChannelEventTypeUpdated:
+					if !s.canReadChannel(ctx, userInfo.User.ID, c.ID) {
+						return
+					}
+				}
+
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+
+				if event.Type == model.ChannelEventTypeDeleted {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// Check if a user can still read a channel, errors are treated as no access.
+func (s *ChannelService) canReadChannel(ctx context.Context, userID int64, channelID int64) bool {
+	c, err := s.DB.GetChannelByID(ctx, channelID)
+	if err != nil {
+		return false
+	}
+
+	return authorizeChannelRead(ctx, s.DB, userID, c) == nil
+}
+
+// Publish an event on the subject of its channel.
+func (s *ChannelService) sendChannelEvent(event *model.ChannelEvent) {
+	if err := s.CH.SendPayload(getChannelEventsChannelID(event.ChannelID), event); err != nil {
+		log.Printf("failed to send channel event via channel manager: %v", err)
+	}
+}
+
+// Publish the current reaction counts of a post.
+func (s *ChannelService) sendReactionsUpdated(ctx context.Context, p db.ChannelPost) error {
+	counts, err := s.DB.GetBatchedChannelPostReactionCounts(ctx, []int64{p.ID})
+	if err != nil {
+		return err
+	}
+
+	reactions := make([]*model.ChannelPostReactionCount, len(counts))
+	for i, c := range counts {
+		reactions[i] = &model.ChannelPostReactionCount{
+			Emoji: c.Emoji,
+			Count: c.ReactionCount,
+		}
+	}
+
+	go s.sendChannelEvent(&model.ChannelEvent{
+		Type:      model.ChannelEventTypeReactionsUpdated,
+		ChannelID: p.ChannelID,
+		PostID:    &p.ID,
+		Reactions: reactions,
+	})
+
+	return nil
+}
+
+// Get the handle to store for a channel, public channels keep a handle that is not taken by another channel
+// and private channels have no handle.
+func (s *ChannelService) checkChannelHandle(ctx context.Context, isPublic bool, handle *string, current *string) (*string, error) {
+	if !isPublic || handle == nil {
+		return nil, nil
+	}
+
+	if current != nil && *current == *handle {
+		return handle, nil
+	}
+
+	exists, err := s.DB.CheckChannelHandleExists(ctx, *handle)
+	if err != nil {
+		return nil, err
+	}
+
+	if exists {
+		return nil, vd.Errors{"handle": errors.New(apperror.INPUT_DUPLICATE)}
+	}
+
+	return handle, nil
+}
+
+// Check if a user can view a channel, public channels are visible to everyone.
+func authorizeChannelRead(ctx context.Context, q db.Querier, userID int64, c db.Channel) error {
+	if c.IsPublic {
+		return nil
+	}
+
+	_, err := authorizeChannelMember(ctx, q, userID, c.ID, false)
+
+	return err
+}
+
+// Get the membership of a user in a channel, when adminsOnly is true the user must also be the owner or an admin of the channel.
+func authorizeChannelMember(ctx context.Context, q db.Querier, userID int64, channelID int64, adminsOnly bool) (db.ChannelMember, error) {
+	member, err := q.GetChannelMember(ctx, db.GetChannelMemberParams{
+		ChannelID: channelID,
+		UserID:    userID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return member, apperror.ErrForbidden
+	}
+	if err != nil {
+		return member, err
+	}
+
+	if adminsOnly && member.Role == model.ChannelRoleSubscriber {
+		return member, apperror.ErrChannelAdminsOnly
+	}
+
+	return member, nil
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestCreateChannelInputValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   CreateChannelInput
+		wantErr bool
+	}{
+		{"private", CreateChannelInput{Name: "News"}, false},
+		{"public with handle", CreateChannelInput{Name: "News", Handle: null.StringFrom("daily_news").Ptr(), IsPublic: true}, false},
+		{"no name", CreateChannelInput{Handle: null.StringFrom("daily_news").Ptr(), IsPublic: true}, true},
+		{"name too long", CreateChannelInput{Name: strings.Repeat("a", 101)}, true},
+		{"public without handle", CreateChannelInput{Name: "News", IsPublic: true}, true},
+		{"handle too short", CreateChannelInput{Name: "News", Handle: null.StringFrom("abc").Ptr(), IsPublic: true}, true},
+		{"handle with uppercase letters", CreateChannelInput{Name: "News", Handle: null.StringFrom("DailyNews").Ptr(), IsPublic: true}, true},
+		{"handle with spaces", CreateChannelInput{Name: "News", Handle: null.StringFrom("daily news").Ptr(), IsPublic: true}, true},
+		{"description too long", CreateChannelInput{Name: "News", Description: null.StringFrom(strings.Repeat("a", 501)).Ptr()}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.input.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/jackc/pgx/v5"
+	"gopkg.in/guregu/null.v4"
+
+	"github.com/thanishsid/dingilink-server/internal/db"
+	"github.com/thanishsid/dingilink-server/internal/types/apperror"
+)
+
+// Check if a user can read a chat, group chats require the user to be a member and direct chats require the other user to exist.
+func authorizeChatRead(ctx context.Context, q db.Querier, userID int64, idType string, id int64) error {
+	switch idType {
+	case "group":
+		isMember, err := q.CheckGroupMember(ctx, db.CheckGroupMemberParams{
+			GroupID: id,
+			UserID:  userID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if !isMember {
+			return apperror.ErrForbidden
+		}
+	case "direct":
+		if _, err := q.GetUser(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Check if a user can send messages to a chat, in addition to the read rules direct chats
+// must not be blocked by either user and the other user must not be deleted.
+// Groups that only allow admins to send messages require the user to be an admin.
+func authorizeChatWrite(ctx context.Context, q db.Querier, userID int64, idType string, id int64) error {
+	if idType == "group" {
+		g, err := q.GetGroupByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		_, err = authorizeGroupMember(ctx, q, userID, g.ID, g.OnlyAdminsCanSendMessages)
+		return err
+	}
+
+	user, err := q.GetUser(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if user.DeletedAt.Valid {
+		return apperror.ErrUserUnavailable
+	}
+
+	blocked, err := q.CheckUsersBlocked(ctx, db.CheckUsersBlockedParams{
+		UserID:      userID,
+		OtherUserID: id,
+	})
+	if err != nil {
+		return err
+	}
+
+	if blocked {
+		return apperror.ErrUserBlocked
+	}
+
+	return nil
+}
+
+// Check if a user is a participant of the chat a message belongs to.
+func authorizeMessageAccess(ctx context.Context, q db.Querier, userID int64, m db.Message) error {
+	if m.GroupID != nil {
+		return authorizeChatRead(ctx, q, userID, "group", *m.GroupID)
+	}
+
+	if m.SenderID != userID && null.IntFromPtr(m.RecipientID).ValueOrZero() != userID {
+		return apperror.ErrForbidden
+	}
+
+	return nil
+}
+
+// Check that the message being replied to belongs to the same chat as the reply.
+func validateReplyMessage(ctx context.Context, q db.Querier, userID int64, idType string, id int64, replyForMessageID int64) error {
+	invalidReplyErr := vd.Errors{"replyForMessageId": errors.New(apperror.INPUT_INVALID)}
+
+	m, err := q.GetMessageByID(ctx, replyForMessageID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return invalidReplyErr
+	}
+	if err != nil {
+		return err
+	}
+
+	switch idType {
+	case "group":
+		if null.IntFromPtr(m.GroupID).ValueOrZero() != id {
+			return invalidReplyErr
+		}
+	case "direct":
+		recipientID := null.IntFromPtr(m.RecipientID).ValueOrZero()
+
+		if !(m.SenderID == userID && recipientID == id) && !(m.SenderID == id && recipientID == userID) {
+			return invalidReplyErr
+		}
+	}
+
+	return nil
+}
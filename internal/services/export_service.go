@@ -0,0 +1,393 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+	"gopkg.in/typ.v4/slices"
+
+	"github.com/thanishsid/dingilink-server/asset"
+	"github.com/thanishsid/dingilink-server/internal/db"
+	"github.com/thanishsid/dingilink-server/internal/model"
+	"github.com/thanishsid/dingilink-server/internal/pkg/security"
+	"github.com/thanishsid/dingilink-server/internal/types/apperror"
+)
+
+const (
+	// Number of messages read from the database at a time while exporting a chat.
+	exportBatchSize = 500
+
+	// Presigned media links of an export are valid for the maximum duration allowed by s3.
+	exportMediaLinkTTL = time.Hour * 24 * 7
+)
+
+type ExportService struct {
+	DB     db.DBQ
+	Upload *UploadService
+}
+
+type ExportChatInput struct {
+	ChatID       string                 `json:"chatId"`
+	Format       model.ChatExportFormat `json:"format"`
+	PresignMedia *bool                  `json:"presignMedia"`
+}
+
+func (i ExportChatInput) Validate() error {
+	return vd.ValidateStruct(&i,
+		vd.Field(&i.ChatID, vd.Required.Error(apperror.INPUT_REQUIRED), vd.By(validateChatID)),
+		vd.Field(&i.Format,
+			vd.Required.Error(apperror.INPUT_REQUIRED),
+			vd.In(model.ChatExportFormat(model.ChatExportFormatJSON), model.ChatExportFormat(model.ChatExportFormatHTML)).Error(apperror.INPUT_INVALID),
+		),
+	)
+}
+
+// Export the full history of a chat into a zip archive, returns the key of the uploaded archive.
+func (s *ExportService) ExportChat(ctx context.Context, input ExportChatInput) (string, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return "", err
+	}
+
+	if err := input.Validate(); err != nil {
+		return "", err
+	}
+
+	idType, id, err := parseChatID(input.ChatID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := authorizeChatRead(ctx, s.DB, userInfo.User.ID, idType, id); err != nil {
+		return "", err
+	}
+
+	info := model.ChatExportInfo{
+		ChatID:     input.ChatID,
+		ExportedAt: time.Now(),
+	}
+
+	var targetUserID, targetGroupID *int64
+
+	switch idType {
+	case "direct":
+		user, err := s.DB.GetUser(ctx, id)
+		if err != nil {
+			return "", err
+		}
+
+		info.ChatName = user.Name
+		targetUserID = &id
+	case "group":
+		group, err := s.DB.GetGroupByID(ctx, id)
+		if err != nil {
+			return "", err
+		}
+
+		info.ChatName = group.Name
+		targetGroupID = &id
+	}
+
+	extension := strings.ToLower(string(input.Format))
+
+	archivePath := path.Join(os.TempDir(), fmt.Sprintf("chat_export_%s.zip", uuid.NewString()))
+	archiveFile, err := os.Create(archivePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create export archive: %w", err)
+	}
+	defer archiveFile.Close()
+	defer os.Remove(archivePath)
+
+	zw := zip.NewWriter(archiveFile)
+
+	entry, err := zw.Create(fmt.Sprintf("chat.%s", extension))
+	if err != nil {
+		return "", fmt.Errorf("failed to create export archive entry: %w", err)
+	}
+
+	var w chatExportWriter = &jsonChatExportWriter{w: entry}
+	if input.Format == model.ChatExportFormatHTML {
+		w = &htmlChatExportWriter{w: entry}
+	}
+
+	if err := w.WriteHeader(info); err != nil {
+		return "", err
+	}
+
+	senderNames := make(map[int64]string)
+	polls := make(map[int64]*model.ChatExportPoll)
+	presignMedia := null.BoolFromPtr(input.PresignMedia).ValueOrZero()
+
+	var cursor *int64
+
+	for {
+		messages, err := s.DB.GetMessagesAfter(ctx, db.GetMessagesAfterParams{
+			TargetUserID:  targetUserID,
+			CurrentUserID: userInfo.User.ID,
+			TargetGroupID: targetGroupID,
+			CursorID:      cursor,
+			ResultLimit:   exportBatchSize,
+		})
+		if err != nil {
+			return "", err
+		}
+
+		if len(messages) == 0 {
+			break
+		}
+
+		if err := s.loadSenderNames(ctx, senderNames, messages); err != nil {
+			return "", err
+		}
+
+		if err := s.loadPolls(ctx, polls, messages); err != nil {
+			return "", err
+		}
+
+		for _, m := range messages {
+			em, err := s.newChatExportMessage(ctx, m, senderNames[m.SenderID], polls[m.ID], presignMedia)
+			if err != nil {
+				return "", err
+			}
+
+			if err := w.WriteMessage(em); err != nil {
+				return "", err
+			}
+		}
+
+		cursor = &messages[len(messages)-1].ID
+	}
+
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	if err := zw.Close(); err != nil {
+		return "", fmt.Errorf("failed to write export archive: %w", err)
+	}
+
+	archiveInfo, err := archiveFile.Stat()
+	if err != nil {
+		return "", fmt.Errorf("failed to stat export archive: %w", err)
+	}
+
+	if _, err := archiveFile.Seek(0, 0); err != nil {
+		return "", fmt.Errorf("failed to seek export archive to start: %w", err)
+	}
+
+	filename := fmt.Sprintf("chat_export_%s_%s.zip", input.ChatID, info.ExportedAt.Format("20060102150405"))
+
+	result, err := s.Upload.UploadObject(ctx, archiveFile, filename, "application/zip", archiveInfo.Size())
+	if err != nil {
+		return "", err
+	}
+
+	return result.Key, nil
+}
+
+// Load the names of the senders of the messages that are not loaded yet.
+func (s *ExportService) loadSenderNames(ctx context.Context, senderNames map[int64]string, messages []db.Message) error {
+	var senderIDs []int64
+
+	for _, m := range messages {
+		if _, ok := senderNames[m.SenderID]; !ok && !slices.Contains(senderIDs, m.SenderID) {
+			senderIDs = append(senderIDs, m.SenderID)
+		}
+	}
+
+	if len(senderIDs) == 0 {
+		return nil
+	}
+
+	senders, err := s.DB.GetBatchedUsers(ctx, senderIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, u := range senders {
+		senderNames[u.ID] = u.Name
+	}
+
+	return nil
+}
+
+// Load the polls of the poll messages keyed by the message id, the polls of the previous batch are cleared.
+func (s *ExportService) loadPolls(ctx context.Context, polls map[int64]*model.ChatExportPoll, messages []db.Message) error {
+	clear(polls)
+
+	var messageIDs []int64
+
+	for _, m := range messages {
+		if m.MessageType == model.MessageTypePoll && !m.DeletedAt.Valid {
+			messageIDs = append(messageIDs, m.ID)
+		}
+	}
+
+	if len(messageIDs) == 0 {
+		return nil
+	}
+
+	batchedPolls, err := s.DB.GetBatchedPolls(ctx, messageIDs)
+	if err != nil {
+		return err
+	}
+
+	pollIDs := make([]int64, len(batchedPolls))
+	for idx, p := range batchedPolls {
+		pollIDs[idx] = p.ID
+	}
+
+	options, err := s.DB.GetBatchedPollOptions(ctx, pollIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range batchedPolls {
+		polls[p.MessageID] = newChatExportPoll(p, options)
+	}
+
+	return nil
+}
+
+// Create the exported poll with the options of the poll in the options of a batch of polls.
+func newChatExportPoll(p db.Poll, options []db.GetBatchedPollOptionsRow) *model.ChatExportPoll {
+	ep := &model.ChatExportPoll{
+		Question:       p.Question,
+		MultipleChoice: p.MultipleChoice,
+		Anonymous:      p.Anonymous,
+		Options:        []model.ChatExportPollOption{},
+	}
+
+	if p.ClosesAt.Valid {
+		ep.ClosesAt = &p.ClosesAt.Time
+	}
+
+	for _, o := range options {
+		if o.PollID != p.ID {
+			continue
+		}
+
+		ep.Options = append(ep.Options, model.ChatExportPollOption{
+			Text:      o.OptionText,
+			VoteCount: len(o.VoterIds),
+		})
+	}
+
+	return ep
+}
+
+func (s *ExportService) newChatExportMessage(ctx context.Context, m db.Message, senderName string, poll *model.ChatExportPoll, presignMedia bool) (model.ChatExportMessage, error) {
+	em := model.ChatExportMessage{
+		ID:                m.ID,
+		Type:              m.MessageType,
+		SenderID:          m.SenderID,
+		SenderName:        senderName,
+		ReplyForMessageID: m.ReplyForMessageID,
+		SentAt:            m.SentAt.Time,
+		Deleted:           m.DeletedAt.Valid,
+	}
+
+	if em.Deleted {
+		return em, nil
+	}
+
+	em.Text = m.TextContent
+	em.Media = m.Media
+	em.Location = m.Location.LatLng()
+	em.Poll = poll
+
+	if m.MessageType == model.MessageTypeSystem && len(m.SystemEvent) > 0 {
+		var event model.SystemEvent
+
+		if err := json.Unmarshal(m.SystemEvent, &event); err != nil {
+			return em, fmt.Errorf("failed to parse system event of message %d: %w", m.ID, err)
+		}
+
+		em.SystemEvent = &event
+	}
+
+	if presignMedia && m.Media != nil {
+		url, err := s.Upload.PresignObjectURL(ctx, *m.Media, exportMediaLinkTTL)
+		if err != nil {
+			return em, err
+		}
+
+		em.MediaURL = &url
+	}
+
+	return em, nil
+}
+
+// Writes the messages of a chat export in a specific format.
+type chatExportWriter interface {
+	WriteHeader(info model.ChatExportInfo) error
+	WriteMessage(m model.ChatExportMessage) error
+	Close() error
+}
+
+// Writes a chat export as a single json object with the messages in a "messages" array.
+type jsonChatExportWriter struct {
+	w            io.Writer
+	wroteMessage bool
+}
+
+func (jw *jsonChatExportWriter) WriteHeader(info model.ChatExportInfo) error {
+	jsn, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+
+	// Open the info object and add the messages array to it.
+	_, err = fmt.Fprintf(jw.w, `%s,"messages":[`, jsn[:len(jsn)-1])
+	return err
+}
+
+func (jw *jsonChatExportWriter) WriteMessage(m model.ChatExportMessage) error {
+	jsn, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	if jw.wroteMessage {
+		if _, err := jw.w.Write([]byte(",")); err != nil {
+			return err
+		}
+	}
+
+	jw.wroteMessage = true
+
+	_, err = jw.w.Write(jsn)
+	return err
+}
+
+func (jw *jsonChatExportWriter) Close() error {
+	_, err := jw.w.Write([]byte("]}"))
+	return err
+}
+
+// Writes a chat export as a html page.
+type htmlChatExportWriter struct {
+	w io.Writer
+}
+
+func (hw *htmlChatExportWriter) WriteHeader(info model.ChatExportInfo) error {
+	return asset.ExportTemplates.ExecuteTemplate(hw.w, "chat-export-header", info)
+}
+
+func (hw *htmlChatExportWriter) WriteMessage(m model.ChatExportMessage) error {
+	return asset.ExportTemplates.ExecuteTemplate(hw.w, "chat-export-message", m)
+}
+
+func (hw *htmlChatExportWriter) Close() error {
+	return asset.ExportTemplates.ExecuteTemplate(hw.w, "chat-export-footer", nil)
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"gopkg.in/guregu/null.v4"
+
+	"github.com/thanishsid/dingilink-server/internal/db"
+	"github.com/thanishsid/dingilink-server/internal/model"
+)
+
+func TestNewChatExportPoll(t *testing.T) {
+	closesAt := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+
+	options := []db.GetBatchedPollOptionsRow{
+		{ID: 1, PollID: 7, OptionText: "Yes", VoterIds: []int64{2, 3}},
+		{ID: 2, PollID: 7, OptionText: "No", VoterIds: []int64{}},
+		{ID: 3, PollID: 8, OptionText: "Other poll", VoterIds: []int64{2}},
+	}
+
+	tests := []struct {
+		name string
+		poll db.Poll
+		want *model.ChatExportPoll
+	}{
+		{
+			name: "with votes",
+			poll: db.Poll{ID: 7, Question: "Lunch?", Anonymous: true},
+			want: &model.ChatExportPoll{
+				Question:  "Lunch?",
+				Anonymous: true,
+				Options: []model.ChatExportPollOption{
+					{Text: "Yes", VoteCount: 2},
+					{Text: "No", VoteCount: 0},
+				},
+			},
+		},
+		{
+			name: "closing",
+			poll: db.Poll{ID: 8, Question: "Where?", MultipleChoice: true, ClosesAt: pgtype.Timestamptz{Time: closesAt, Valid: true}},
+			want: &model.ChatExportPoll{
+				Question:       "Where?",
+				MultipleChoice: true,
+				ClosesAt:       &closesAt,
+				Options:        []model.ChatExportPollOption{{Text: "Other poll", VoteCount: 1}},
+			},
+		},
+		{
+			name: "without options",
+			poll: db.Poll{ID: 9, Question: "Empty?"},
+			want: &model.ChatExportPoll{Question: "Empty?", Options: []model.ChatExportPollOption{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newChatExportPoll(tt.poll, options); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newChatExportPoll() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewChatExportMessage(t *testing.T) {
+	poll := &model.ChatExportPoll{
+		Question: "Lunch?",
+		Options:  []model.ChatExportPollOption{{Text: "Yes", VoteCount: 2}},
+	}
+
+	groupName := "Friends"
+	sentAt := pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	deletedAt := pgtype.Timestamptz{Time: time.Now(), Valid: true}
+
+	tests := []struct {
+		name        string
+		message     db.Message
+		poll        *model.ChatExportPoll
+		wantText    *string
+		wantPoll    *model.ChatExportPoll
+		wantEvent   *model.SystemEvent
+		wantDeleted bool
+		wantErr     bool
+	}{
+		{
+			name:     "text",
+			message:  db.Message{ID: 1, MessageType: model.MessageTypeText, TextContent: null.StringFrom("hello").Ptr(), SentAt: sentAt},
+			wantText: null.StringFrom("hello").Ptr(),
+		},
+		{
+			name:     "poll",
+			message:  db.Message{ID: 2, MessageType: model.MessageTypePoll, SentAt: sentAt},
+			poll:     poll,
+			wantPoll: poll,
+		},
+		{
+			name:      "system",
+			message:   db.Message{ID: 3, MessageType: model.MessageTypeSystem, SystemEvent: []byte(`{"kind":"group_renamed","groupName":"Friends"}`), SentAt: sentAt},
+			wantEvent: &model.SystemEvent{Kind: model.SystemEventKindGroupRenamed, GroupName: &groupName},
+		},
+		{
+			name:    "invalid system event",
+			message: db.Message{ID: 4, MessageType: model.MessageTypeSystem, SystemEvent: []byte(`{`), SentAt: sentAt},
+			wantErr: true,
+		},
+		{
+			name:        "deleted poll",
+			message:     db.Message{ID: 5, MessageType: model.MessageTypePoll, SentAt: sentAt, DeletedAt: deletedAt},
+			poll:        poll,
+			wantDeleted: true,
+		},
+	}
+
+	s := &ExportService{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.newChatExportMessage(context.Background(), tt.message, "Jane", tt.poll, false)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newChatExportMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if got.ID != tt.message.ID || got.Type != tt.message.MessageType || got.SenderName != "Jane" || got.Deleted != tt.wantDeleted {
+				t.Errorf("newChatExportMessage() = %+v", got)
+			}
+
+			if !reflect.DeepEqual(got.Text, tt.wantText) {
+				t.Errorf("newChatExportMessage() text = %v, want %v", got.Text, tt.wantText)
+			}
+
+			if !reflect.DeepEqual(got.Poll, tt.wantPoll) {
+				t.Errorf("newChatExportMessage() poll = %+v, want %+v", got.Poll, tt.wantPoll)
+			}
+
+			if !reflect.DeepEqual(got.SystemEvent, tt.wantEvent) {
+				t.Errorf("newChatExportMessage() system event = %+v, want %+v", got.SystemEvent, tt.wantEvent)
+			}
+		})
+	}
+}
+
+func TestChatExportWriters(t *testing.T) {
+	groupName := "Friends"
+
+	messages := []model.ChatExportMessage{
+		{
+			ID:         1,
+			Type:       model.MessageTypePoll,
+			SenderName: "Jane",
+			Poll: &model.ChatExportPoll{
+				Question: "Lunch?",
+				Options:  []model.ChatExportPollOption{{Text: "Pizza", VoteCount: 2}},
+			},
+		},
+		{
+			ID:          2,
+			Type:        model.MessageTypeSystem,
+			SenderName:  "Jane",
+			SystemEvent: &model.SystemEvent{Kind: model.SystemEventKindGroupRenamed, GroupName: &groupName},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		new      func(w *bytes.Buffer) chatExportWriter
+		wantJSON bool
+		want     []string
+	}{
+		{
+			name:     "json",
+			new:      func(w *bytes.Buffer) chatExportWriter { return &jsonChatExportWriter{w: w} },
+			wantJSON: true,
+			want:     []string{`"question":"Lunch?"`, `{"text":"Pizza","voteCount":2}`, `"systemEvent":{"kind":"group_renamed","groupName":"Friends"}`},
+		},
+		{
+			name: "html",
+			new:  func(w *bytes.Buffer) chatExportWriter { return &htmlChatExportWriter{w: w} },
+			want: []string{"Poll: Lunch?", "Pizza - 2 votes", "group_renamed: Friends"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			w := tt.new(&buf)
+
+			if err := w.WriteHeader(model.ChatExportInfo{ChatID: "group_1", ChatName: "Friends"}); err != nil {
+				t.Fatalf("WriteHeader() error = %v", err)
+			}
+
+			for _, m := range messages {
+				if err := w.WriteMessage(m); err != nil {
+					t.Fatalf("WriteMessage() error = %v", err)
+				}
+			}
+
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			if tt.wantJSON && !json.Valid(buf.Bytes()) {
+				t.Fatalf("export is not valid json: %s", buf.String())
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("export does not contain %s", want)
+				}
+			}
+		})
+	}
+}
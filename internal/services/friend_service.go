@@ -0,0 +1,583 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/thanishsid/dingilink-server/internal/db"
+	"github.com/thanishsid/dingilink-server/internal/model"
+	"github.com/thanishsid/dingilink-server/internal/pkg/messaging"
+	"github.com/thanishsid/dingilink-server/internal/pkg/security"
+	"github.com/thanishsid/dingilink-server/internal/types/apperror"
+)
+
+const (
+	defaultFriendsPageSize int64 = 50
+	maxFriendsPageSize     int64 = 200
+)
+
+type FriendService struct {
+	DB db.DBQ
+	CH *messaging.ChannelManager[*model.FriendEvent]
+}
+
+func getFriendChannelID(userID int64) string {
+	return fmt.Sprintf("user_%d.friend_events", userID)
+}
+
+// Convert a pending friendship row into a friend request model.
+func newFriendRequest(f db.Friendship) *model.FriendRequest {
+	return &model.FriendRequest{
+		ID:          f.ID,
+		SenderID:    f.UserID,
+		RecipientID: f.FriendID,
+		CreatedAt:   f.CreatedAt,
+	}
+}
+
+// Convert an accepted friendship row into a friend model seen from the given user.
+func newFriend(f db.Friendship, userID int64) *model.Friend {
+	friendID := f.FriendID
+	if friendID == userID {
+		friendID = f.UserID
+	}
+
+	return &model.Friend{
+		FriendshipID: f.ID,
+		UserID:       friendID,
+		Since:        f.UpdatedAt,
+	}
+}
+
+// Send a friend request to a user, when the user has already sent a request to the sender the request is accepted instead.
+func (s *FriendService) SendFriendRequest(ctx context.Context, userID string) (*model.FriendRequest, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	recipientID := parseID(userID)
+
+	if recipientID == userInfo.User.ID {
+		return nil, vd.Errors{"userId": errors.New(apperror.INPUT_INVALID)}
+	}
+
+	recipient, err := s.DB.GetUser(ctx, recipientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if recipient.DeletedAt.Valid {
+		return nil, apperror.ErrUserUnavailable
+	}
+
+	blocked, err := s.DB.CheckUsersBlocked(ctx, db.CheckUsersBlockedParams{
+		UserID:      userInfo.User.ID,
+		OtherUserID: recipientID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if blocked {
+		return nil, apperror.ErrCannotBefriendUser
+	}
+
+	existing, err := s.DB.GetFriendshipBetween(ctx, db.GetFriendshipBetweenParams{
+		UserID:      userInfo.User.ID,
+		OtherUserID: recipientID,
+	})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	if err == nil {
+		if existing.Status == model.FriendshipStatusPending && existing.UserID == recipientID {
+			if _, err := s.acceptFriendRequest(ctx, recipientID, userInfo.User.ID); err != nil {
+				return nil, err
+			}
+
+			return newFriendRequest(existing), nil
+		}
+
+		return nil, vd.Errors{"userId": errors.New(apperror.INPUT_DUPLICATE)}
+	}
+
+	f, err := s.DB.InsertFriendRequest(ctx, db.InsertFriendRequestParams{
+		SenderID:    userInfo.User.ID,
+		RecipientID: recipientID,
+	})
+	// No row is returned when a friendship between the users was created concurrently.
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, vd.Errors{"userId": errors.New(apperror.INPUT_DUPLICATE)}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	request := newFriendRequest(f)
+
+	go func() {
+		s.sendFriendEvent(recipientID, &model.FriendEvent{
+			Type:    model.FriendEventTypeRequestReceived,
+			UserID:  userInfo.User.ID,
+			Request: request,
+		})
+
+		s.sendFriendEvent(userInfo.User.ID, &model.FriendEvent{
+			Type:    model.FriendEventTypeRequestSent,
+			UserID:  recipientID,
+			Request: request,
+		})
+	}()
+
+	return request, nil
+}
+
+// Accept a friend request sent to the user.
+func (s *FriendService) AcceptFriendRequest(ctx context.Context, userID string) (*model.Friend, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.acceptFriendRequest(ctx, parseID(userID), userInfo.User.ID)
+}
+
+// Decline a friend request sent to the user.
+func (s *FriendService) DeclineFriendRequest(ctx context.Context, userID string) error {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return err
+	}
+
+	return s.deleteFriendRequest(ctx, parseID(userID), userInfo.User.ID, model.FriendEventTypeRequestDeclined)
+}
+
+// Cancel a friend request sent by the user.
+func (s *FriendService) CancelFriendRequest(ctx context.Context, userID string) error {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return err
+	}
+
+	return s.deleteFriendRequest(ctx, userInfo.User.ID, parseID(userID), model.FriendEventTypeRequestCancelled)
+}
+
+// Remove a user from the friends of the user.
+func (s *FriendService) Unfriend(ctx context.Context, userID string) error {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return err
+	}
+
+	friendID := parseID(userID)
+
+	deleted, err := s.DB.DeleteFriendship(ctx, db.DeleteFriendshipParams{
+		UserID:   userInfo.User.ID,
+		FriendID: friendID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if deleted == 0 {
+		return vd.Errors{"userId": errors.New(apperror.INPUT_INVALID)}
+	}
+
+	go func() {
+		s.sendFriendEvent(userInfo.User.ID, &model.FriendEvent{
+			Type:   model.FriendEventTypeUnfriended,
+			UserID: friendID,
+		})
+
+		s.sendFriendEvent(friendID, &model.FriendEvent{
+			Type:   model.FriendEventTypeUnfriended,
+			UserID: userInfo.User.ID,
+		})
+	}()
+
+	return nil
+}
+
+// Block a user, the friendship and pending friend requests between the users are removed
+// and the users can no longer message, call or befriend each other.
+func (s *FriendService) BlockUser(ctx context.Context, userID string) error {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return err
+	}
+
+	blockedUserID := parseID(userID)
+
+	if blockedUserID == userInfo.User.ID {
+		return vd.Errors{"userId": errors.New(apperror.INPUT_INVALID)}
+	}
+
+	if _, err := s.DB.GetUser(ctx, blockedUserID); err != nil {
+		return err
+	}
+
+	tx, err := s.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	removed, err := tx.DeleteFriendshipsBetween(ctx, db.DeleteFriendshipsBetweenParams{
+		UserID:      userInfo.User.ID,
+		OtherUserID: blockedUserID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.InsertUserBlock(ctx, db.InsertUserBlockParams{
+		UserID:        userInfo.User.ID,
+		BlockedUserID: blockedUserID,
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	go func() {
+		s.sendFriendEvent(userInfo.User.ID, &model.FriendEvent{
+			Type:   model.FriendEventTypeBlocked,
+			UserID: blockedUserID,
+		})
+
+		// The blocked user only sees the removal of the friendship or friend request.
+		for _, f := range removed {
+			eventType := model.FriendEventType(model.FriendEventTypeRequestCancelled)
+			if f.Status == model.FriendshipStatusAccepted {
+				eventType = model.FriendEventTypeUnfriended
+			}
+
+			s.sendFriendEvent(blockedUserID, &model.FriendEvent{
+				Type:   eventType,
+				UserID: userInfo.User.ID,
+			})
+		}
+	}()
+
+	return nil
+}
+
+// Unblock a user blocked by the user, the previous friendship is not restored.
+func (s *FriendService) UnblockUser(ctx context.Context, userID string) error {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return err
+	}
+
+	blockedUserID := parseID(userID)
+
+	deleted, err := s.DB.DeleteUserBlock(ctx, db.DeleteUserBlockParams{
+		UserID:        userInfo.User.ID,
+		BlockedUserID: blockedUserID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if deleted == 0 {
+		return vd.Errors{"userId": errors.New(apperror.INPUT_INVALID)}
+	}
+
+	go s.sendFriendEvent(userInfo.User.ID, &model.FriendEvent{
+		Type:   model.FriendEventTypeUnblocked,
+		UserID: blockedUserID,
+	})
+
+	return nil
+}
+
+type GetFriendsInput struct {
+	First *int64  `json:"first"`
+	After *string `json:"after"`
+}
+
+func (i GetFriendsInput) Validate() error {
+	return vd.ValidateStruct(&i,
+		vd.Field(&i.First,
+			vd.Min(int64(1)).Error(apperror.INPUT_TOO_LOW),
+			vd.Max(maxFriendsPageSize).Error(apperror.INPUT_TOO_HIGH),
+		),
+	)
+}
+
+// Get the friends of the user from the most recently added.
+func (s *FriendService) GetFriends(ctx context.Context, input GetFriendsInput) (*model.FriendConnection, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	friendships, hasNextPage, err := s.getFriendshipsPage(input, func(cursor *int64, limit int64) ([]db.Friendship, error) {
+		return s.DB.GetFriendsPage(ctx, db.GetFriendsPageParams{
+			UserID:      userInfo.User.ID,
+			Cursor:      cursor,
+			ResultLimit: limit,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	edges := make([]model.FriendEdge, len(friendships))
+
+	for idx, f := range friendships {
+		edges[idx] = model.FriendEdge{
+			Node:   newFriend(f, userInfo.User.ID),
+			Cursor: fmt.Sprintf("%d", f.ID),
+		}
+	}
+
+	connection := model.FriendConnection{
+		Edges: edges,
+		PageInfo: model.PageInfo{
+			HasNextPage:     hasNextPage,
+			HasPreviousPage: input.After != nil,
+		},
+	}
+
+	if len(edges) > 0 {
+		connection.PageInfo.StartCursor = &edges[0].Cursor
+		connection.PageInfo.EndCursor = &edges[len(edges)-1].Cursor
+	}
+
+	return &connection, nil
+}
+
+// Get the pending friend requests sent to the user from the newest.
+func (s *FriendService) GetIncomingFriendRequests(ctx context.Context, input GetFriendsInput) (*model.FriendRequestConnection, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getFriendRequests(input, func(cursor *int64, limit int64) ([]db.Friendship, error) {
+		return s.DB.GetIncomingFriendRequestsPage(ctx, db.GetIncomingFriendRequestsPageParams{
+			UserID:      userInfo.User.ID,
+			Cursor:      cursor,
+			ResultLimit: limit,
+		})
+	})
+}
+
+// Get the pending friend requests sent by the user from the newest.
+func (s *FriendService) GetOutgoingFriendRequests(ctx context.Context, input GetFriendsInput) (*model.FriendRequestConnection, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getFriendRequests(input, func(cursor *int64, limit int64) ([]db.Friendship, error) {
+		return s.DB.GetOutgoingFriendRequestsPage(ctx, db.GetOutgoingFriendRequestsPageParams{
+			UserID:      userInfo.User.ID,
+			Cursor:      cursor,
+			ResultLimit: limit,
+		})
+	})
+}
+
+// Get the users blocked by the user from the most recently blocked.
+func (s *FriendService) GetBlockedUsers(ctx context.Context, input GetFriendsInput) (*model.BlockedUserConnection, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	friendships, hasNextPage, err := s.getFriendshipsPage(input, func(cursor *int64, limit int64) ([]db.Friendship, error) {
+		return s.DB.GetBlockedUsersPage(ctx, db.GetBlockedUsersPageParams{
+			UserID:      userInfo.User.ID,
+			Cursor:      cursor,
+			ResultLimit: limit,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	edges := make([]model.BlockedUserEdge, len(friendships))
+
+	for idx, f := range friendships {
+		edges[idx] = model.BlockedUserEdge{
+			Node: &model.BlockedUser{
+				UserID:    f.FriendID,
+				BlockedAt: f.CreatedAt,
+			},
+			Cursor: fmt.Sprintf("%d", f.ID),
+		}
+	}
+
+	connection := model.BlockedUserConnection{
+		Edges: edges,
+		PageInfo: model.PageInfo{
+			HasNextPage:     hasNextPage,
+			HasPreviousPage: input.After != nil,
+		},
+	}
+
+	if len(edges) > 0 {
+		connection.PageInfo.StartCursor = &edges[0].Cursor
+		connection.PageInfo.EndCursor = &edges[len(edges)-1].Cursor
+	}
+
+	return &connection, nil
+}
+
+// Subscribe to the friend requests and friendship changes of the user.
+func (s *FriendService) SubscribeToFriendEvents(ctx context.Context) (<-chan *model.FriendEvent, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	clientID, ch, err := s.CH.Subscribe(getFriendChannelID(userInfo.User.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		<-ctx.Done()
+		s.CH.Unsubscribe(clientID)
+	}()
+
+	return ch, nil
+}
+
+// Accept the pending friend request from the sender to the recipient and notify both users.
+func (s *FriendService) acceptFriendRequest(ctx context.Context, senderID int64, recipientID int64) (*model.Friend, error) {
+	f, err := s.DB.AcceptFriendRequest(ctx, db.AcceptFriendRequestParams{
+		SenderID:    senderID,
+		RecipientID: recipientID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, vd.Errors{"userId": errors.New(apperror.INPUT_INVALID)}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		s.sendFriendEvent(senderID, &model.FriendEvent{
+			Type:   model.FriendEventTypeRequestAccepted,
+			UserID: recipientID,
+			Friend: newFriend(f, senderID),
+		})
+
+		s.sendFriendEvent(recipientID, &model.FriendEvent{
+			Type:   model.FriendEventTypeRequestAccepted,
+			UserID: senderID,
+			Friend: newFriend(f, recipientID),
+		})
+	}()
+
+	return newFriend(f, recipientID), nil
+}
+
+// Delete the pending friend request from the sender to the recipient and notify both users.
+func (s *FriendService) deleteFriendRequest(ctx context.Context, senderID int64, recipientID int64, eventType model.FriendEventType) error {
+	deleted, err := s.DB.DeleteFriendRequest(ctx, db.DeleteFriendRequestParams{
+		SenderID:    senderID,
+		RecipientID: recipientID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if deleted == 0 {
+		return vd.Errors{"userId": errors.New(apperror.INPUT_INVALID)}
+	}
+
+	go func() {
+		s.sendFriendEvent(senderID, &model.FriendEvent{
+			Type:   eventType,
+			UserID: recipientID,
+		})
+
+		s.sendFriendEvent(recipientID, &model.FriendEvent{
+			Type:   eventType,
+			UserID: senderID,
+		})
+	}()
+
+	return nil
+}
+
+// Get a page of friend requests using the given query.
+func (s *FriendService) getFriendRequests(input GetFriendsInput, query func(cursor *int64, limit int64) ([]db.Friendship, error)) (*model.FriendRequestConnection, error) {
+	friendships, hasNextPage, err := s.getFriendshipsPage(input, query)
+	if err != nil {
+		return nil, err
+	}
+
+	edges := make([]model.FriendRequestEdge, len(friendships))
+
+	for idx, f := range friendships {
+		edges[idx] = model.FriendRequestEdge{
+			Node:   newFriendRequest(f),
+			Cursor: fmt.Sprintf("%d", f.ID),
+		}
+	}
+
+	connection := model.FriendRequestConnection{
+		Edges: edges,
+		PageInfo: model.PageInfo{
+			HasNextPage:     hasNextPage,
+			HasPreviousPage: input.After != nil,
+		},
+	}
+
+	if len(edges) > 0 {
+		connection.PageInfo.StartCursor = &edges[0].Cursor
+		connection.PageInfo.EndCursor = &edges[len(edges)-1].Cursor
+	}
+
+	return &connection, nil
+}
+
+// Validate the pagination input and run the query requesting one extra row to find out if there are more rows.
+func (s *FriendService) getFriendshipsPage(input GetFriendsInput, query func(cursor *int64, limit int64) ([]db.Friendship, error)) ([]db.Friendship, bool, error) {
+	if err := input.Validate(); err != nil {
+		return nil, false, err
+	}
+
+	cursor, err := parseCursor(input.After)
+	if err != nil {
+		return nil, false, vd.Errors{"after": errors.New(apperror.INPUT_INVALID)}
+	}
+
+	limit := defaultFriendsPageSize
+	if input.First != nil {
+		limit = *input.First
+	}
+
+	friendships, err := query(cursor, limit+1)
+	if err != nil {
+		return nil, false, err
+	}
+
+	hasNextPage := int64(len(friendships)) > limit
+	if hasNextPage {
+		friendships = friendships[:limit]
+	}
+
+	return friendships, hasNextPage, nil
+}
+
+// Send a friend event to all devices of a user.
+func (s *FriendService) sendFriendEvent(userID int64, event *model.FriendEvent) {
+	if err := s.CH.SendPayload(getFriendChannelID(userID), event); err != nil {
+		log.Printf("failed to send friend event via channel manager: %v", err)
+	}
+}
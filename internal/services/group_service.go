@@ -0,0 +1,1531 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"gopkg.in/guregu/null.v4"
+
+	"github.com/thanishsid/dingilink-server/internal/db"
+	"github.com/thanishsid/dingilink-server/internal/model"
+	"github.com/thanishsid/dingilink-server/internal/pkg/messaging"
+	"github.com/thanishsid/dingilink-server/internal/pkg/security"
+	"github.com/thanishsid/dingilink-server/internal/types/apperror"
+)
+
+const (
+	defaultGroupMembersPageSize int64 = 50
+	maxGroupMembersPageSize     int64 = 200
+)
+
+type GroupService struct {
+	DB      db.DBQ
+	CH      *messaging.ChannelManager[*model.GroupEvent]
+	Message *MessageService
+}
+
+func getGroupChannelID(userID int64) string {
+	return fmt.Sprintf("user_%d.group_events", userID)
+}
+
+// Convert a group row into a group model.
+func newGroup(g db.Group) *model.Group {
+	return &model.Group{
+		ID:          g.ID,
+		Name:        g.Name,
+		Description: g.Description,
+		Image:       g.Image,
+		CreatedBy:   g.CreatedBy,
+		JoinPolicy:  model.GroupJoinPolicy(g.JoinPolicy),
+		Policies: model.GroupPolicies{
+			OnlyAdminsCanSendMessages: g.OnlyAdminsCanSendMessages,
+			OnlyAdminsCanEditInfo:     g.OnlyAdminsCanEditInfo,
+			OnlyAdminsCanAddMembers:   g.OnlyAdminsCanAddMembers,
+		},
+	}
+}
+
+// Convert a join request row into a join request model.
+func newGroupJoinRequest(r db.GroupJoinRequest) *model.GroupJoinRequest {
+	return &model.GroupJoinRequest{
+		ID:        r.ID,
+		GroupID:   r.GroupID,
+		UserID:    r.UserID,
+		Status:    model.GroupJoinRequestStatus(r.Status),
+		CreatedAt: r.CreatedAt,
+		DecidedBy: r.DecidedBy,
+		DecidedAt: r.DecidedAt,
+	}
+}
+
+// Convert a group invite row into a group invite model.
+func newGroupInvite(i db.GroupInvite) *model.GroupInvite {
+	return &model.GroupInvite{
+		ID:        i.ID,
+		GroupID:   i.GroupID,
+		Code:      i.Code,
+		CreatedBy: i.CreatedBy,
+		MaxUses:   i.MaxUses,
+		UseCount:  i.UseCount,
+		ExpiresAt: i.ExpiresAt,
+		RevokedAt: i.RevokedAt,
+		CreatedAt: i.CreatedAt,
+	}
+}
+
+type CreateGroupInput struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+	Image       *string `json:"image"`
+	MemberIDs   []int64 `json:"memberIds"`
+}
+
+func (i CreateGroupInput) Validate() error {
+	return vd.ValidateStruct(&i,
+		vd.Field(&i.Name,
+			vd.Required.Error(apperror.INPUT_REQUIRED),
+			vd.RuneLength(1, 100).Error(apperror.INPUT_TOO_HIGH),
+		),
+		vd.Field(&i.Description, vd.RuneLength(0, 500).Error(apperror.INPUT_TOO_HIGH)),
+		vd.Field(&i.MemberIDs, vd.Length(0, 255).Error(apperror.INPUT_TOO_HIGH)),
+	)
+}
+
+// Create a group, the creator becomes the owner and an admin of the group.
+func (s *GroupService) CreateGroup(ctx context.Context, input CreateGroupInput) (*model.Group, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	memberIDs, err := validateGroupCandidates(ctx, s.DB, userInfo.User.ID, "memberIds", input.MemberIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	g, err := tx.InsertGroup(ctx, db.InsertGroupParams{
+		Name:        input.Name,
+		Description: input.Description,
+		Image:       input.Image,
+		CreatedBy:   userInfo.User.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := insertGroupMember(ctx, tx, db.InsertGroupMemberParams{
+		GroupID: g.ID,
+		UserID:  userInfo.User.ID,
+		IsAdmin: true,
+	}); err != nil {
+		return nil, err
+	}
+
+	for _, memberID := range memberIDs {
+		if _, err := insertGroupMember(ctx, tx, db.InsertGroupMemberParams{
+			GroupID: g.ID,
+			UserID:  memberID,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	group := newGroup(g)
+
+	if _, err := s.Message.SendSystemMessage(ctx, SystemMessageParams{
+		ActorID: userInfo.User.ID,
+		GroupID: &g.ID,
+		Event: model.SystemEvent{
+			Kind:          model.SystemEventKindGroupCreated,
+			TargetUserIDs: memberIDs,
+			GroupName:     &g.Name,
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	go s.sendGroupEvent(append(memberIDs, userInfo.User.ID), &model.GroupEvent{
+		Type:    model.GroupEventTypeCreated,
+		GroupID: g.ID,
+		Group:   group,
+	})
+
+	return group, nil
+}
+
+type UpdateGroupInput struct {
+	GroupID          int64                  `json:"groupId"`
+	Name             string                 `json:"name"`
+	Description      *string                `json:"description"`
+	ClearDescription *bool                  `json:"clearDescription"`
+	Image            *string                `json:"image"`
+	ClearImage       *bool                  `json:"clearImage"`
+	JoinPolicy       *model.GroupJoinPolicy `json:"joinPolicy"`
+}
+
+func (i UpdateGroupInput) Validate() error {
+	return vd.ValidateStruct(&i,
+		vd.Field(&i.GroupID, vd.Required.Error(apperror.INPUT_REQUIRED)),
+		vd.Field(&i.Name,
+			vd.Required.Error(apperror.INPUT_REQUIRED),
+			vd.RuneLength(1, 100).Error(apperror.INPUT_TOO_HIGH),
+		),
+		vd.Field(&i.Description,
+			vd.Nil.When(null.BoolFromPtr(i.ClearDescription).ValueOrZero()).Error(apperror.INPUT_INVALID),
+			vd.RuneLength(0, 500).Error(apperror.INPUT_TOO_HIGH),
+		),
+		vd.Field(&i.Image, vd.Nil.When(null.BoolFromPtr(i.ClearImage).ValueOrZero()).Error(apperror.INPUT_INVALID)),
+		vd.Field(&i.JoinPolicy,
+			vd.In(
+				model.GroupJoinPolicy(model.GroupJoinPolicyOpen),
+				model.GroupJoinPolicy(model.GroupJoinPolicyApprovalRequired),
+			).Error(apperror.INPUT_INVALID),
+		),
+	)
+}
+
+// Update the name, description, image and join policy of a group, members can update the info of a group
+// unless it is restricted to admins, only admins can change the join policy. The description, image and
+// join policy are kept when they are not given, the description and image are only removed with the clear flags.
+func (s *GroupService) UpdateGroup(ctx context.Context, input UpdateGroupInput) (*model.Group, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.DB.GetGroupByID(ctx, input.GroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := authorizeGroupMember(ctx, s.DB, userInfo.User.ID, existing.ID, existing.OnlyAdminsCanEditInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	joinPolicy := existing.JoinPolicy
+	if input.JoinPolicy != nil {
+		joinPolicy = string(*input.JoinPolicy)
+	}
+
+	// Members can edit the info of a group when allowed but only admins can change who can join.
+	if joinPolicy != existing.JoinPolicy && !member.IsAdmin {
+		return nil, apperror.ErrGroupAdminsOnly
+	}
+
+	g, err := s.DB.UpdateGroup(ctx, db.UpdateGroupParams{
+		Name:             input.Name,
+		ClearDescription: null.BoolFromPtr(input.ClearDescription).ValueOrZero(),
+		Description:      input.Description,
+		ClearImage:       null.BoolFromPtr(input.ClearImage).ValueOrZero(),
+		Image:            input.Image,
+		JoinPolicy:       joinPolicy,
+		GroupID:          input.GroupID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var events []model.SystemEvent
+
+	if g.Name != existing.Name {
+		events = append(events, model.SystemEvent{
+			Kind:      model.SystemEventKindGroupRenamed,
+			GroupName: &g.Name,
+		})
+	}
+
+	if !null.StringFromPtr(g.Image).Equal(null.StringFromPtr(existing.Image)) {
+		events = append(events, model.SystemEvent{
+			Kind:       model.SystemEventKindGroupIconChanged,
+			GroupImage: g.Image,
+		})
+	}
+
+	for _, event := range events {
+		if _, err := s.Message.SendSystemMessage(ctx, SystemMessageParams{
+			ActorID: userInfo.User.ID,
+			GroupID: &g.ID,
+			Event:   event,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	group := newGroup(g)
+
+	if err := s.notifyGroupMembers(ctx, &model.GroupEvent{
+		Type:    model.GroupEventTypeUpdated,
+		GroupID: g.ID,
+		Group:   group,
+	}); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// Delete a group along with its messages, only the owner can delete a group.
+func (s *GroupService) DeleteGroup(ctx context.Context, groupID string) error {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return err
+	}
+
+	g, err := s.DB.GetGroupByID(ctx, parseID(groupID))
+	if err != nil {
+		return err
+	}
+
+	if g.CreatedBy != userInfo.User.ID {
+		return apperror.ErrForbidden
+	}
+
+	memberIDs, err := s.getGroupMemberIDs(ctx, g.ID)
+	if err != nil {
+		return err
+	}
+
+	chatID := fmt.Sprintf("group_%d", g.ID)
+
+	tx, err := s.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := tx.DeleteGroup(ctx, g.ID); err != nil {
+		return err
+	}
+
+	// Drafts and chat settings are keyed by the chat id and are not removed along with the group.
+	if err := tx.DeleteChatDraftsByChatID(ctx, chatID); err != nil {
+		return err
+	}
+
+	if err := tx.DeleteChatSettingsByChatID(ctx, chatID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	go func() {
+		s.sendGroupEvent(memberIDs, &model.GroupEvent{
+			Type:    model.GroupEventTypeDeleted,
+			GroupID: g.ID,
+			Group:   newGroup(g),
+		})
+
+		for _, memberID := range memberIDs {
+			s.Message.sendChatListEvent(memberID, &model.ChatListEvent{ChatID: chatID})
+		}
+	}()
+
+	return nil
+}
+
+type UpdateGroupPoliciesInput struct {
+	GroupID                   int64 `json:"groupId"`
+	OnlyAdminsCanSendMessages *bool `json:"onlyAdminsCanSendMessages"`
+	OnlyAdminsCanEditInfo     *bool `json:"onlyAdminsCanEditInfo"`
+	OnlyAdminsCanAddMembers   *bool `json:"onlyAdminsCanAddMembers"`
+}
+
+func (i UpdateGroupPoliciesInput) Validate() error {
+	return vd.ValidateStruct(&i,
+		vd.Field(&i.GroupID, vd.Required.Error(apperror.INPUT_REQUIRED)),
+	)
+}
+
+// Update the policies of a group, only admins can update the policies. Policies that are not given are kept.
+func (s *GroupService) UpdateGroupPolicies(ctx context.Context, input UpdateGroupPoliciesInput) (*model.Group, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.DB.GetGroupByID(ctx, input.GroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := authorizeGroupMember(ctx, s.DB, userInfo.User.ID, existing.ID, true); err != nil {
+		return nil, err
+	}
+
+	params := db.UpdateGroupPoliciesParams{
+		OnlyAdminsCanSendMessages: existing.OnlyAdminsCanSendMessages,
+		OnlyAdminsCanEditInfo:     existing.OnlyAdminsCanEditInfo,
+		OnlyAdminsCanAddMembers:   existing.OnlyAdminsCanAddMembers,
+		GroupID:                   existing.ID,
+	}
+
+	if input.OnlyAdminsCanSendMessages != nil {
+		params.OnlyAdminsCanSendMessages = *input.OnlyAdminsCanSendMessages
+	}
+
+	if input.OnlyAdminsCanEditInfo != nil {
+		params.OnlyAdminsCanEditInfo = *input.OnlyAdminsCanEditInfo
+	}
+
+	if input.OnlyAdminsCanAddMembers != nil {
+		params.OnlyAdminsCanAddMembers = *input.OnlyAdminsCanAddMembers
+	}
+
+	g, err := s.DB.UpdateGroupPolicies(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	group := newGroup(g)
+
+	if err := s.notifyGroupMembers(ctx, &model.GroupEvent{
+		Type:    model.GroupEventTypeUpdated,
+		GroupID: g.ID,
+		Group:   group,
+	}); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+type AddGroupMembersInput struct {
+	GroupID int64   `json:"groupId"`
+	UserIDs []int64 `json:"userIds"`
+}
+
+func (i AddGroupMembersInput) Validate() error {
+	return vd.ValidateStruct(&i,
+		vd.Field(&i.GroupID, vd.Required.Error(apperror.INPUT_REQUIRED)),
+		vd.Field(&i.UserIDs,
+			vd.Required.Error(apperror.INPUT_REQUIRED),
+			vd.Length(1, 255).Error(apperror.INPUT_TOO_HIGH),
+		),
+	)
+}
+
+// Add users to a group, members can add members unless it is restricted to admins. Users who are already members are skipped.
+func (s *GroupService) AddGroupMembers(ctx context.Context, input AddGroupMembersInput) (*model.Group, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	g, err := s.DB.GetGroupByID(ctx, input.GroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := authorizeGroupMember(ctx, s.DB, userInfo.User.ID, g.ID, g.OnlyAdminsCanAddMembers); err != nil {
+		return nil, err
+	}
+
+	candidateIDs, err := validateGroupCandidates(ctx, s.DB, userInfo.User.ID, "userIds", input.UserIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	addedIDs := make([]int64, 0, len(candidateIDs))
+
+	for _, candidateID := range candidateIDs {
+		added, err := insertGroupMember(ctx, tx, db.InsertGroupMemberParams{
+			GroupID: g.ID,
+			UserID:  candidateID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if added > 0 {
+			addedIDs = append(addedIDs, candidateID)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	group := newGroup(g)
+
+	if len(addedIDs) == 0 {
+		return group, nil
+	}
+
+	if _, err := s.Message.SendSystemMessage(ctx, SystemMessageParams{
+		ActorID: userInfo.User.ID,
+		GroupID: &g.ID,
+		Event: model.SystemEvent{
+			Kind:          model.SystemEventKindMemberAdded,
+			TargetUserIDs: addedIDs,
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := s.notifyGroupMembers(ctx, &model.GroupEvent{
+		Type:    model.GroupEventTypeMemberAdded,
+		GroupID: g.ID,
+		Group:   group,
+		UserIDs: addedIDs,
+	}); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// Remove a member from a group, only admins can remove members.
+// The owner can not be removed and only the owner can remove other admins.
+func (s *GroupService) RemoveGroupMember(ctx context.Context, groupID string, userID string) error {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return err
+	}
+
+	memberID := parseID(userID)
+
+	if memberID == userInfo.User.ID {
+		return vd.Errors{"userId": errors.New(apperror.INPUT_INVALID)}
+	}
+
+	g, err := s.DB.GetGroupByID(ctx, parseID(groupID))
+	if err != nil {
+		return err
+	}
+
+	if _, err := authorizeGroupMember(ctx, s.DB, userInfo.User.ID, g.ID, true); err != nil {
+		return err
+	}
+
+	member, err := s.DB.GetGroupMember(ctx, db.GetGroupMemberParams{
+		GroupID: g.ID,
+		UserID:  memberID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return vd.Errors{"userId": errors.New(apperror.INPUT_INVALID)}
+	}
+	if err != nil {
+		return err
+	}
+
+	if member.UserID == g.CreatedBy || (member.IsAdmin && userInfo.User.ID != g.CreatedBy) {
+		return apperror.ErrForbidden
+	}
+
+	if err := s.deleteGroupMember(ctx, g.ID, memberID); err != nil {
+		return err
+	}
+
+	if _, err := s.Message.SendSystemMessage(ctx, SystemMessageParams{
+		ActorID: userInfo.User.ID,
+		GroupID: &g.ID,
+		Event: model.SystemEvent{
+			Kind:          model.SystemEventKindMemberRemoved,
+			TargetUserIDs: []int64{memberID},
+		},
+	}); err != nil {
+		return err
+	}
+
+	return s.notifyGroupMembers(ctx, &model.GroupEvent{
+		Type:    model.GroupEventTypeMemberRemoved,
+		GroupID: g.ID,
+		Group:   newGroup(g),
+		UserIDs: []int64{memberID},
+	}, memberID)
+}
+
+// Leave a group, the owner must transfer the ownership of the group before leaving.
+func (s *GroupService) LeaveGroup(ctx context.Context, groupID string) error {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return err
+	}
+
+	g, err := s.DB.GetGroupByID(ctx, parseID(groupID))
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeChatRead(ctx, s.DB, userInfo.User.ID, "group", g.ID); err != nil {
+		return err
+	}
+
+	if g.CreatedBy == userInfo.User.ID {
+		return apperror.ErrGroupOwnerCannotLeave
+	}
+
+	if err := s.deleteGroupMember(ctx, g.ID, userInfo.User.ID); err != nil {
+		return err
+	}
+
+	if _, err := s.Message.SendSystemMessage(ctx, SystemMessageParams{
+		ActorID: userInfo.User.ID,
+		GroupID: &g.ID,
+		Event: model.SystemEvent{
+			Kind: model.SystemEventKindMemberLeft,
+		},
+	}); err != nil {
+		return err
+	}
+
+	return s.notifyGroupMembers(ctx, &model.GroupEvent{
+		Type:    model.GroupEventTypeMemberRemoved,
+		GroupID: g.ID,
+		Group:   newGroup(g),
+		UserIDs: []int64{userInfo.User.ID},
+	}, userInfo.User.ID)
+}
+
+type SetGroupAdminInput struct {
+	GroupID int64 `json:"groupId"`
+	UserID  int64 `json:"userId"`
+	IsAdmin bool  `json:"isAdmin"`
+}
+
+func (i SetGroupAdminInput) Validate() error {
+	return vd.ValidateStruct(&i,
+		vd.Field(&i.GroupID, vd.Required.Error(apperror.INPUT_REQUIRED)),
+		vd.Field(&i.UserID, vd.Required.Error(apperror.INPUT_REQUIRED)),
+	)
+}
+
+// Promote a member to admin or demote an admin, only admins can promote members and only the owner can demote admins.
+func (s *GroupService) SetGroupAdmin(ctx context.Context, input SetGroupAdminInput) (*model.Group, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := authorizeGroupMember(ctx, s.DB, userInfo.User.ID, input.GroupID, true); err != nil {
+		return nil, err
+	}
+
+	g, err := s.DB.GetGroupByID(ctx, input.GroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The owner is always an admin of the group.
+	if input.UserID == g.CreatedBy || (!input.IsAdmin && userInfo.User.ID != g.CreatedBy) {
+		return nil, apperror.ErrForbidden
+	}
+
+	updated, err := s.DB.SetGroupMemberAdmin(ctx, db.SetGroupMemberAdminParams{
+		IsAdmin: input.IsAdmin,
+		GroupID: g.ID,
+		UserID:  input.UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if updated == 0 {
+		return nil, vd.Errors{"userId": errors.New(apperror.INPUT_INVALID)}
+	}
+
+	group := newGroup(g)
+
+	if err := s.notifyGroupMembers(ctx, &model.GroupEvent{
+		Type:    model.GroupEventTypeMemberUpdated,
+		GroupID: g.ID,
+		Group:   group,
+		UserIDs: []int64{input.UserID},
+	}); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// Transfer the ownership of a group to another member, the new owner is made an admin of the group.
+func (s *GroupService) TransferGroupOwnership(ctx context.Context, groupID string, userID string) (*model.Group, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	newOwnerID := parseID(userID)
+
+	if newOwnerID == userInfo.User.ID {
+		return nil, vd.Errors{"userId": errors.New(apperror.INPUT_INVALID)}
+	}
+
+	g, err := s.DB.GetGroupByID(ctx, parseID(groupID))
+	if err != nil {
+		return nil, err
+	}
+
+	if g.CreatedBy != userInfo.User.ID {
+		return nil, apperror.ErrForbidden
+	}
+
+	tx, err := s.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	updated, err := tx.SetGroupMemberAdmin(ctx, db.SetGroupMemberAdminParams{
+		IsAdmin: true,
+		GroupID: g.ID,
+		UserID:  newOwnerID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if updated == 0 {
+		return nil, vd.Errors{"userId": errors.New(apperror.INPUT_INVALID)}
+	}
+
+	if err := tx.UpdateGroupOwner(ctx, db.UpdateGroupOwnerParams{
+		OwnerID: newOwnerID,
+		GroupID: g.ID,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	g.CreatedBy = newOwnerID
+	group := newGroup(g)
+
+	if err := s.notifyGroupMembers(ctx, &model.GroupEvent{
+		Type:    model.GroupEventTypeMemberUpdated,
+		GroupID: g.ID,
+		Group:   group,
+		UserIDs: []int64{userInfo.User.ID, newOwnerID},
+	}); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+type CreateGroupInviteInput struct {
+	GroupID   int64      `json:"groupId"`
+	MaxUses   *int32     `json:"maxUses"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+func (i CreateGroupInviteInput) Validate() error {
+	return vd.ValidateStruct(&i,
+		vd.Field(&i.GroupID, vd.Required.Error(apperror.INPUT_REQUIRED)),
+		vd.Field(&i.MaxUses, vd.Min(int32(1)).Error(apperror.INPUT_TOO_LOW)),
+		vd.Field(&i.ExpiresAt, vd.Min(time.Now()).Error(apperror.INPUT_TOO_LOW)),
+	)
+}
+
+// Create an invite link for a group, only admins can create invites.
+func (s *GroupService) CreateGroupInvite(ctx context.Context, input CreateGroupInviteInput) (*model.GroupInvite, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := authorizeGroupMember(ctx, s.DB, userInfo.User.ID, input.GroupID, true); err != nil {
+		return nil, err
+	}
+
+	code, err := generateCode()
+	if err != nil {
+		return nil, err
+	}
+
+	invite, err := s.DB.InsertGroupInvite(ctx, db.InsertGroupInviteParams{
+		GroupID:   input.GroupID,
+		Code:      code,
+		CreatedBy: userInfo.User.ID,
+		MaxUses:   input.MaxUses,
+		ExpiresAt: pgtype.Timestamptz{
+			Time:  null.TimeFromPtr(input.ExpiresAt).ValueOrZero(),
+			Valid: input.ExpiresAt != nil,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newGroupInvite(invite), nil
+}
+
+// Revoke an invite link of a group, only admins can revoke invites.
+func (s *GroupService) RevokeGroupInvite(ctx context.Context, inviteID string) error {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return err
+	}
+
+	invite, err := s.DB.GetGroupInviteByID(ctx, parseID(inviteID))
+	if err != nil {
+		return err
+	}
+
+	if _, err := authorizeGroupMember(ctx, s.DB, userInfo.User.ID, invite.GroupID, true); err != nil {
+		return err
+	}
+
+	_, err = s.DB.RevokeGroupInvite(ctx, invite.ID)
+	return err
+}
+
+// Get the invite links of a group along with the revoked and expired ones, only admins can view invites.
+func (s *GroupService) GetGroupInvites(ctx context.Context, groupID string) ([]*model.GroupInvite, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	id := parseID(groupID)
+
+	if _, err := authorizeGroupMember(ctx, s.DB, userInfo.User.ID, id, true); err != nil {
+		return nil, err
+	}
+
+	invites, err := s.DB.GetGroupInvites(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.GroupInvite, len(invites))
+	for i, invite := range invites {
+		result[i] = newGroupInvite(invite)
+	}
+
+	return result, nil
+}
+
+// Get the public details of the group of an invite without joining the group.
+func (s *GroupService) GetGroupInvitePreview(ctx context.Context, code string) (*model.GroupInvitePreview, error) {
+	if _, err := security.Authorize(ctx, security.User); err != nil {
+		return nil, err
+	}
+
+	invite, err := s.getUsableGroupInvite(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := s.DB.GetGroupByID(ctx, invite.GroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	memberCount, err := s.DB.GetGroupMemberCount(ctx, g.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.GroupInvitePreview{
+		GroupID:     g.ID,
+		Name:        g.Name,
+		Description: g.Description,
+		Image:       g.Image,
+		MemberCount: memberCount,
+		JoinPolicy:  model.GroupJoinPolicy(g.JoinPolicy),
+	}, nil
+}
+
+// Join a group through an invite, joining a group the user is already a member of does not use the invite.
+// Groups that require approval get a pending join request instead, the request takes up a use of the invite.
+func (s *GroupService) JoinGroupByInvite(ctx context.Context, code string) (*model.JoinGroupResult, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	invite, err := s.getUsableGroupInvite(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := s.DB.GetGroupByID(ctx, invite.GroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	group := newGroup(g)
+
+	isMember, err := s.DB.CheckGroupMember(ctx, db.CheckGroupMemberParams{
+		GroupID: g.ID,
+		UserID:  userInfo.User.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if isMember {
+		return &model.JoinGroupResult{Group: group}, nil
+	}
+
+	if group.JoinPolicy == model.GroupJoinPolicyApprovalRequired {
+		joinRequest, err := s.requestToJoinGroup(ctx, userInfo.User.ID, invite)
+		if err != nil {
+			return nil, err
+		}
+
+		return &model.JoinGroupResult{JoinRequest: joinRequest}, nil
+	}
+
+	tx, err := s.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// The use count is checked and increased in a single update so concurrent joins can not exceed the max uses.
+	used, err := tx.UseGroupInvite(ctx, invite.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if used == 0 {
+		return nil, apperror.ErrInvalidGroupInvite
+	}
+
+	if _, err := insertGroupMember(ctx, tx, db.InsertGroupMemberParams{
+		GroupID: g.ID,
+		UserID:  userInfo.User.ID,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.InsertGroupInviteUse(ctx, db.InsertGroupInviteUseParams{
+		InviteID: invite.ID,
+		UserID:   userInfo.User.ID,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.Message.SendSystemMessage(ctx, SystemMessageParams{
+		ActorID: userInfo.User.ID,
+		GroupID: &g.ID,
+		Event: model.SystemEvent{
+			Kind: model.SystemEventKindMemberJoined,
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := s.notifyGroupMembers(ctx, &model.GroupEvent{
+		Type:    model.GroupEventTypeMemberAdded,
+		GroupID: g.ID,
+		Group:   group,
+		UserIDs: []int64{userInfo.User.ID},
+	}); err != nil {
+		return nil, err
+	}
+
+	return &model.JoinGroupResult{Group: group}, nil
+}
+
+// Create a pending join request through an invite and notify the admins of the group,
+// the existing request is returned when the user already has a pending request.
+func (s *GroupService) requestToJoinGroup(ctx context.Context, userID int64, invite db.GroupInvite) (*model.GroupJoinRequest, error) {
+	existing, err := s.DB.GetPendingGroupJoinRequest(ctx, db.GetPendingGroupJoinRequestParams{
+		GroupID: invite.GroupID,
+		UserID:  userID,
+	})
+	if err == nil {
+		return newGroupJoinRequest(existing), nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	tx, err := s.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	used, err := tx.UseGroupInvite(ctx, invite.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if used == 0 {
+		return nil, apperror.ErrInvalidGroupInvite
+	}
+
+	r, err := tx.InsertGroupJoinRequest(ctx, db.InsertGroupJoinRequestParams{
+		GroupID:  invite.GroupID,
+		UserID:   userID,
+		InviteID: &invite.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	joinRequest := newGroupJoinRequest(r)
+
+	adminIDs, err := s.getGroupAdminIDs(ctx, invite.GroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	go s.sendGroupEvent(adminIDs, &model.GroupEvent{
+		Type:        model.GroupEventTypeJoinRequested,
+		GroupID:     invite.GroupID,
+		JoinRequest: joinRequest,
+	})
+
+	return joinRequest, nil
+}
+
+// Get the pending join requests of a group, only admins can view join requests.
+func (s *GroupService) GetPendingJoinRequests(ctx context.Context, groupID string) ([]*model.GroupJoinRequest, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	id := parseID(groupID)
+
+	if _, err := authorizeGroupMember(ctx, s.DB, userInfo.User.ID, id, true); err != nil {
+		return nil, err
+	}
+
+	requests, err := s.DB.GetPendingGroupJoinRequests(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.GroupJoinRequest, len(requests))
+	for i, r := range requests {
+		result[i] = newGroupJoinRequest(r)
+	}
+
+	return result, nil
+}
+
+// Approve a pending join request and add the requester to the group, only admins can approve join requests.
+func (s *GroupService) ApproveJoinRequest(ctx context.Context, requestID string) (*model.GroupJoinRequest, error) {
+	return s.decideJoinRequest(ctx, requestID, model.GroupJoinRequestStatusApproved)
+}
+
+// Reject a pending join request, only admins can reject join requests.
+func (s *GroupService) RejectJoinRequest(ctx context.Context, requestID string) (*model.GroupJoinRequest, error) {
+	return s.decideJoinRequest(ctx, requestID, model.GroupJoinRequestStatusRejected)
+}
+
+func (s *GroupService) decideJoinRequest(ctx context.Context, requestID string, status model.GroupJoinRequestStatus) (*model.GroupJoinRequest, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := s.DB.GetGroupJoinRequestByID(ctx, parseID(requestID))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := authorizeGroupMember(ctx, s.DB, userInfo.User.ID, r.GroupID, true); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	r, err = tx.DecideGroupJoinRequest(ctx, db.DecideGroupJoinRequestParams{
+		Status:    string(status),
+		DecidedBy: &userInfo.User.ID,
+		RequestID: r.ID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, vd.Errors{"requestId": errors.New(apperror.INPUT_INVALID)}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	approved := status == model.GroupJoinRequestStatusApproved
+
+	if approved {
+		if _, err := insertGroupMember(ctx, tx, db.InsertGroupMemberParams{
+			GroupID: r.GroupID,
+			UserID:  r.UserID,
+		}); err != nil {
+			return nil, err
+		}
+
+		if r.InviteID != nil {
+			if err := tx.InsertGroupInviteUse(ctx, db.InsertGroupInviteUseParams{
+				InviteID: *r.InviteID,
+				UserID:   r.UserID,
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	g, err := s.DB.GetGroupByID(ctx, r.GroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	group := newGroup(g)
+	joinRequest := newGroupJoinRequest(r)
+
+	var eventType model.GroupEventType = model.GroupEventTypeJoinRequestRejected
+
+	if approved {
+		eventType = model.GroupEventTypeJoinRequestApproved
+
+		if _, err := s.Message.SendSystemMessage(ctx, SystemMessageParams{
+			ActorID: userInfo.User.ID,
+			GroupID: &g.ID,
+			Event: model.SystemEvent{
+				Kind:          model.SystemEventKindMemberAdded,
+				TargetUserIDs: []int64{r.UserID},
+			},
+		}); err != nil {
+			return nil, err
+		}
+
+		if err := s.notifyGroupMembers(ctx, &model.GroupEvent{
+			Type:    model.GroupEventTypeMemberAdded,
+			GroupID: g.ID,
+			Group:   group,
+			UserIDs: []int64{r.UserID},
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	adminIDs, err := s.getGroupAdminIDs(ctx, g.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	go s.sendGroupEvent(append(adminIDs, r.UserID), &model.GroupEvent{
+		Type:        eventType,
+		GroupID:     g.ID,
+		Group:       group,
+		JoinRequest: joinRequest,
+	})
+
+	return joinRequest, nil
+}
+
+// Get an invite by its code and check that it is not revoked, expired or used up.
+func (s *GroupService) getUsableGroupInvite(ctx context.Context, code string) (db.GroupInvite, error) {
+	invite, err := s.DB.GetGroupInviteByCode(ctx, code)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return invite, apperror.ErrInvalidGroupInvite
+	}
+	if err != nil {
+		return invite, err
+	}
+
+	if invite.RevokedAt.Valid ||
+		(invite.ExpiresAt.Valid && !invite.ExpiresAt.Time.After(time.Now())) ||
+		(invite.MaxUses != nil && invite.UseCount >= *invite.MaxUses) {
+		return invite, apperror.ErrInvalidGroupInvite
+	}
+
+	return invite, nil
+}
+
+// Get a group, only members can view a group.
+func (s *GroupService) GetGroup(ctx context.Context, groupID string) (*model.Group, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	id := parseID(groupID)
+
+	if err := authorizeChatRead(ctx, s.DB, userInfo.User.ID, "group", id); err != nil {
+		return nil, err
+	}
+
+	g, err := s.DB.GetGroupByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return newGroup(g), nil
+}
+
+type GetGroupMembersInput struct {
+	First      *int64  `json:"first"`
+	After      *string `json:"after"`
+	Search     *string `json:"search"`
+	AdminsOnly *bool   `json:"adminsOnly"`
+}
+
+func (i GetGroupMembersInput) Validate() error {
+	return vd.ValidateStruct(&i,
+		vd.Field(&i.First,
+			vd.Min(int64(1)).Error(apperror.INPUT_TOO_LOW),
+			vd.Max(maxGroupMembersPageSize).Error(apperror.INPUT_TOO_HIGH),
+		),
+	)
+}
+
+// Create the query params of a page of group members, the search is matched literally.
+func newGroupMembersPageParams(groupID int64, input GetGroupMembersInput) (db.GetGroupMembersPageParams, error) {
+	params := db.GetGroupMembersPageParams{
+		GroupID:     groupID,
+		AdminsOnly:  null.BoolFromPtr(input.AdminsOnly).ValueOrZero(),
+		Search:      newLikeSearch(input.Search),
+		ResultLimit: defaultGroupMembersPageSize,
+	}
+
+	if input.First != nil {
+		params.ResultLimit = *input.First
+	}
+
+	if input.After != nil {
+		var err error
+
+		params.CursorJoinedAt, params.CursorID, err = parseGroupMemberCursor(*input.After)
+		if err != nil {
+			return params, vd.Errors{"after": errors.New(apperror.INPUT_INVALID)}
+		}
+	}
+
+	return params, nil
+}
+
+// Get a page of the members of a group ordered by join time, only members can view the members of a group.
+// Search matches anywhere in the name or the start of the username, which suits mention autocomplete.
+func (s *GroupService) GetGroupMembers(ctx context.Context, groupID int64, input GetGroupMembersInput) (*model.GroupMemberConnection, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := authorizeChatRead(ctx, s.DB, userInfo.User.ID, "group", groupID); err != nil {
+		return nil, err
+	}
+
+	params, err := newGroupMembersPageParams(groupID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := params.ResultLimit
+
+	// One extra row is requested to find out if there are more members.
+	params.ResultLimit++
+
+	members, err := s.DB.GetGroupMembersPage(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	hasNextPage := int64(len(members)) > limit
+	if hasNextPage {
+		members = members[:limit]
+	}
+
+	edges := make([]model.GroupMemberEdge, len(members))
+
+	for idx, m := range members {
+		edges[idx] = model.GroupMemberEdge{
+			Node: &model.GroupMember{
+				ID:       m.ID,
+				UserID:   m.UserID,
+				IsAdmin:  m.IsAdmin,
+				IsOwner:  m.IsOwner,
+				JoinedAt: m.JoinedAt,
+			},
+			Cursor: fmt.Sprintf("%d:%d", m.JoinedAt.Time.UnixMicro(), m.ID),
+		}
+	}
+
+	connection := model.GroupMemberConnection{
+		Edges: edges,
+		PageInfo: model.PageInfo{
+			HasNextPage:     hasNextPage,
+			HasPreviousPage: input.After != nil,
+		},
+	}
+
+	if len(edges) > 0 {
+		connection.PageInfo.StartCursor = &edges[0].Cursor
+		connection.PageInfo.EndCursor = &edges[len(edges)-1].Cursor
+	}
+
+	return &connection, nil
+}
+
+// Get the groups the current user is a member of.
+func (s *GroupService) GetMyGroups(ctx context.Context) ([]*model.Group, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := s.DB.GetUserGroups(ctx, userInfo.User.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.Group, len(groups))
+	for i, g := range groups {
+		result[i] = newGroup(g)
+	}
+
+	return result, nil
+}
+
+// Subscribe to the events of the groups the current user is a member of.
+func (s *GroupService) SubscribeToGroupEvents(ctx context.Context) (<-chan *model.GroupEvent, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	clientID, ch, err := s.CH.Subscribe(getGroupChannelID(userInfo.User.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		<-ctx.Done()
+		s.CH.Unsubscribe(clientID)
+	}()
+
+	return ch, nil
+}
+
+// Send a group event to all devices of the given users.
+func (s *GroupService) sendGroupEvent(userIDs []int64, event *model.GroupEvent) {
+	for _, userID := range userIDs {
+		if err := s.CH.SendPayload(getGroupChannelID(userID), event); err != nil {
+			log.Printf("failed to send group event via channel manager: %v", err)
+		}
+	}
+}
+
+// Send a group event to the current members of a group and the given users e.g. removed members.
+func (s *GroupService) notifyGroupMembers(ctx context.Context, event *model.GroupEvent, userIDs ...int64) error {
+	memberIDs, err := s.getGroupMemberIDs(ctx, event.GroupID)
+	if err != nil {
+		return err
+	}
+
+	go s.sendGroupEvent(append(memberIDs, userIDs...), event)
+
+	return nil
+}
+
+// Remove a user from a group along with the group chat in the user's chat list.
+func (s *GroupService) deleteGroupMember(ctx context.Context, groupID int64, userID int64) error {
+	chatID := fmt.Sprintf("group_%d", groupID)
+
+	tx, err := s.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.DeleteGroupMember(ctx, db.DeleteGroupMemberParams{
+		GroupID: groupID,
+		UserID:  userID,
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.DeleteConversationMember(ctx, db.DeleteConversationMemberParams{
+		UserID: userID,
+		ChatID: chatID,
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	go s.Message.sendChatListEvent(userID, &model.ChatListEvent{ChatID: chatID})
+
+	return nil
+}
+
+// Add a user to a group along with the group chat in the user's chat list, the chat is added to the
+// chat lists of all members when the first message of the group creates its conversation.
+func insertGroupMember(ctx context.Context, q db.Querier, params db.InsertGroupMemberParams) (int64, error) {
+	added, err := q.InsertGroupMember(ctx, params)
+	if err != nil || added == 0 {
+		return added, err
+	}
+
+	err = q.InsertGroupConversationMember(ctx, db.InsertGroupConversationMemberParams{
+		UserID:  params.UserID,
+		GroupID: params.GroupID,
+	})
+
+	return added, err
+}
+
+func (s *GroupService) getGroupAdminIDs(ctx context.Context, groupID int64) ([]int64, error) {
+	members, err := s.DB.GetGroupMembers(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	var adminIDs []int64
+	for _, m := range members {
+		if m.IsAdmin {
+			adminIDs = append(adminIDs, m.UserID)
+		}
+	}
+
+	return adminIDs, nil
+}
+
+func (s *GroupService) getGroupMemberIDs(ctx context.Context, groupID int64) ([]int64, error) {
+	members, err := s.DB.GetGroupMembers(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	memberIDs := make([]int64, len(members))
+	for i, m := range members {
+		memberIDs[i] = m.UserID
+	}
+
+	return memberIDs, nil
+}
+
+// Get the membership of a user in a group, when adminsOnly is true the user must also be an admin of the group.
+func authorizeGroupMember(ctx context.Context, q db.Querier, userID int64, groupID int64, adminsOnly bool) (db.GroupMember, error) {
+	member, err := q.GetGroupMember(ctx, db.GetGroupMemberParams{
+		GroupID: groupID,
+		UserID:  userID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return member, apperror.ErrForbidden
+	}
+	if err != nil {
+		return member, err
+	}
+
+	if adminsOnly && !member.IsAdmin {
+		return member, apperror.ErrGroupAdminsOnly
+	}
+
+	return member, nil
+}
+
+// Check that the users being added to a group by the actor exist, are not deleted and have not blocked or been blocked by the actor.
+// Returns the ids of the users without duplicates and without the actor, field is the input field reported on failure.
+func validateGroupCandidates(ctx context.Context, q db.Querier, actorID int64, field string, userIDs []int64) ([]int64, error) {
+	invalidMembersErr := vd.Errors{field: errors.New(apperror.INPUT_INVALID)}
+
+	candidateIDs := make([]int64, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if userID != actorID && !slices.Contains(candidateIDs, userID) {
+			candidateIDs = append(candidateIDs, userID)
+		}
+	}
+
+	if len(candidateIDs) == 0 {
+		return candidateIDs, nil
+	}
+
+	users, err := q.GetBatchedUsers(ctx, candidateIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(users) != len(candidateIDs) {
+		return nil, invalidMembersErr
+	}
+
+	for _, user := range users {
+		if user.DeletedAt.Valid {
+			return nil, invalidMembersErr
+		}
+
+		blocked, err := q.CheckUsersBlocked(ctx, db.CheckUsersBlockedParams{
+			UserID:      actorID,
+			OtherUserID: user.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if blocked {
+			return nil, invalidMembersErr
+		}
+	}
+
+	return candidateIDs, nil
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"testing"
+
+	"gopkg.in/guregu/null.v4"
+
+	"github.com/thanishsid/dingilink-server/internal/model"
+)
+
+func TestUpdateGroupInputValidate(t *testing.T) {
+	approval := model.GroupJoinPolicy(model.GroupJoinPolicyApprovalRequired)
+	unknown := model.GroupJoinPolicy("unknown")
+
+	tests := []struct {
+		name    string
+		input   UpdateGroupInput
+		wantErr bool
+	}{
+		{"name only", UpdateGroupInput{GroupID: 1, Name: "Friends"}, false},
+		{"all fields", UpdateGroupInput{GroupID: 1, Name: "Friends", Description: null.StringFrom("Hi").Ptr(), Image: null.StringFrom("image.png").Ptr(), JoinPolicy: &approval}, false},
+		{"clear description", UpdateGroupInput{GroupID: 1, Name: "Friends", ClearDescription: null.BoolFrom(true).Ptr()}, false},
+		{"clear image", UpdateGroupInput{GroupID: 1, Name: "Friends", ClearImage: null.BoolFrom(true).Ptr()}, false},
+		{"keep image with false clear flag", UpdateGroupInput{GroupID: 1, Name: "Friends", Image: null.StringFrom("image.png").Ptr(), ClearImage: null.BoolFrom(false).Ptr()}, false},
+		{"clear and set description", UpdateGroupInput{GroupID: 1, Name: "Friends", Description: null.StringFrom("Hi").Ptr(), ClearDescription: null.BoolFrom(true).Ptr()}, true},
+		{"clear and set image", UpdateGroupInput{GroupID: 1, Name: "Friends", Image: null.StringFrom("image.png").Ptr(), ClearImage: null.BoolFrom(true).Ptr()}, true},
+		{"no group", UpdateGroupInput{Name: "Friends"}, true},
+		{"no name", UpdateGroupInput{GroupID: 1}, true},
+		{"unknown join policy", UpdateGroupInput{GroupID: 1, Name: "Friends", JoinPolicy: &unknown}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.input.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewGroupMembersPageParams(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          GetGroupMembersInput
+		wantSearch     *string
+		wantLimit      int64
+		wantAdminsOnly bool
+		wantCursorID   *int64
+		wantErr        bool
+	}{
+		{name: "defaults", input: GetGroupMembersInput{}, wantLimit: defaultGroupMembersPageSize},
+		{name: "search", input: GetGroupMembersInput{Search: null.StringFrom(" jane ").Ptr()}, wantSearch: null.StringFrom("jane").Ptr(), wantLimit: defaultGroupMembersPageSize},
+		{name: "blank search", input: GetGroupMembersInput{Search: null.StringFrom("  ").Ptr()}, wantLimit: defaultGroupMembersPageSize},
+		{name: "wildcard search", input: GetGroupMembersInput{Search: null.StringFrom("%_").Ptr()}, wantSearch: null.StringFrom(`\%\_`).Ptr(), wantLimit: defaultGroupMembersPageSize},
+		{name: "admins only", input: GetGroupMembersInput{First: null.IntFrom(5).Ptr(), AdminsOnly: null.BoolFrom(true).Ptr()}, wantLimit: 5, wantAdminsOnly: true},
+		{name: "after", input: GetGroupMembersInput{After: null.StringFrom("1714559400000123:12").Ptr()}, wantLimit: defaultGroupMembersPageSize, wantCursorID: null.IntFrom(12).Ptr()},
+		{name: "invalid after", input: GetGroupMembersInput{After: null.StringFrom("12").Ptr()}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newGroupMembersPageParams(3, tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newGroupMembersPageParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if got.GroupID != 3 || got.ResultLimit != tt.wantLimit || got.AdminsOnly != tt.wantAdminsOnly {
+				t.Errorf("newGroupMembersPageParams() = %+v", got)
+			}
+
+			if !null.StringFromPtr(got.Search).Equal(null.StringFromPtr(tt.wantSearch)) {
+				t.Errorf("newGroupMembersPageParams() search = %v, want %v", null.StringFromPtr(got.Search), null.StringFromPtr(tt.wantSearch))
+			}
+
+			if !null.IntFromPtr(got.CursorID).Equal(null.IntFromPtr(tt.wantCursorID)) {
+				t.Errorf("newGroupMembersPageParams() cursor id = %v, want %v", null.IntFromPtr(got.CursorID), null.IntFromPtr(tt.wantCursorID))
+			}
+		})
+	}
+}
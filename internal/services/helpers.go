@@ -1,10 +1,247 @@
 package services
 
 import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"gopkg.in/guregu/null.v4"
+
+	"github.com/thanishsid/dingilink-server/internal/db"
+	"github.com/thanishsid/dingilink-server/internal/model"
+	"github.com/thanishsid/dingilink-server/internal/types/apperror"
 )
 
 func parseID(idString string) int64 {
 	id, _ := strconv.ParseInt(idString, 10, 64)
 	return id
 }
+
+// Generate an unguessable url safe code from 16 random bytes.
+func generateCode() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Split a chat id such as "direct_1" or "group_1" into its type and id.
+func parseChatID(chatID string) (string, int64, error) {
+	splitId := strings.Split(chatID, "_")
+	if len(splitId) != 2 {
+		return "", 0, fmt.Errorf("invalid id")
+	}
+
+	idType := splitId[0]
+
+	if idType != "direct" && idType != "group" {
+		return "", 0, fmt.Errorf("invalid id")
+	}
+
+	id, err := strconv.ParseInt(splitId[1], 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid id")
+	}
+
+	return idType, id, nil
+}
+
+// Convert a chat list row into a direct or group chat preview.
+func newChatPreview(c db.GetChatsRow) model.ChatPreview {
+	return newChatPreviewPayload(c).Build()
+}
+
+// Convert a chat list row into the preview payload carried by chat list events.
+func newChatPreviewPayload(c db.GetChatsRow) *model.ChatPreviewPayload {
+	var draft *model.ChatDraft
+
+	if c.DraftText != nil {
+		draft = &model.ChatDraft{
+			ChatID:            c.ChatID,
+			Text:              *c.DraftText,
+			ReplyForMessageID: c.DraftReplyForMessageID,
+			UpdatedAt:         c.DraftUpdatedAt,
+		}
+	}
+
+	return &model.ChatPreviewPayload{
+		IsGroupChat:        c.IsGroupChat,
+		TargetID:           c.TargetID,
+		LastMessageID:      c.LastMessageID,
+		UnreadMessageCount: int64(c.UnreadCount),
+		Draft:              draft,
+		Settings: model.ChatSettings{
+			ChatID:     c.ChatID,
+			ArchivedAt: c.ArchivedAt,
+			PinOrder:   c.PinOrder,
+			MutedUntil: c.MutedUntil,
+		},
+	}
+}
+
+// Validation rule for chat ids.
+func validateChatID(value interface{}) error {
+	chatID, _ := value.(string)
+
+	if _, _, err := parseChatID(chatID); err != nil {
+		return errors.New(apperror.INPUT_INVALID)
+	}
+
+	return nil
+}
+
+// Parse an optional pagination cursor into an id.
+func parseCursor(cursor *string) (*int64, error) {
+	if cursor == nil {
+		return nil, nil
+	}
+
+	id, err := strconv.ParseInt(*cursor, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &id, nil
+}
+
+// Parse a chat list cursor into the pin order and the last message id of the chat.
+func parseChatCursor(cursor string) (int32, *int64, error) {
+	pinOrderString, messageIDString, found := strings.Cut(cursor, ":")
+	if !found {
+		return 0, nil, fmt.Errorf("invalid cursor")
+	}
+
+	pinOrder, err := strconv.ParseInt(pinOrderString, 10, 32)
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid cursor")
+	}
+
+	messageID, err := strconv.ParseInt(messageIDString, 10, 64)
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid cursor")
+	}
+
+	return int32(pinOrder), &messageID, nil
+}
+
+// Parse a group members cursor into the join time and the id of the member.
+func parseGroupMemberCursor(cursor string) (pgtype.Timestamptz, *int64, error) {
+	joinedAtString, memberIDString, found := strings.Cut(cursor, ":")
+	if !found {
+		return pgtype.Timestamptz{}, nil, fmt.Errorf("invalid cursor")
+	}
+
+	joinedAt, err := strconv.ParseInt(joinedAtString, 10, 64)
+	if err != nil {
+		return pgtype.Timestamptz{}, nil, fmt.Errorf("invalid cursor")
+	}
+
+	memberID, err := strconv.ParseInt(memberIDString, 10, 64)
+	if err != nil {
+		return pgtype.Timestamptz{}, nil, fmt.Errorf("invalid cursor")
+	}
+
+	return pgtype.Timestamptz{Time: time.UnixMicro(joinedAt), Valid: true}, &memberID, nil
+}
+
+// Returns a copy of the slice in reverse order.
+func reversed[S ~[]E, E any](s S) S {
+	r := slices.Clone(s)
+	slices.Reverse(r)
+	return r
+}
+
+// Create a message builder from a message row.
+func newMessageBuilder(m db.Message) model.MessageBuilder {
+	return model.MessageBuilder{
+		ID:                m.ID,
+		SenderID:          m.SenderID,
+		RecipientID:       m.RecipientID,
+		GroupID:           m.GroupID,
+		MessageType:       m.MessageType,
+		TextContent:       m.TextContent,
+		Media:             m.Media,
+		Location:          m.Location,
+		ReplyForMessageID: m.ReplyForMessageID,
+		SentAt:            m.SentAt,
+		DeletedAt:         m.DeletedAt,
+		DeletedBy:         m.DeletedBy,
+		SystemEvent:       m.SystemEvent,
+	}
+}
+
+// Key of the conversation a message belongs to, direct conversations are keyed by both users so the key is the same for each of them.
+func conversationKey(m db.Message) string {
+	if m.GroupID != nil {
+		return fmt.Sprintf("group_%d", *m.GroupID)
+	}
+
+	recipientID := null.IntFromPtr(m.RecipientID).ValueOrZero()
+
+	return fmt.Sprintf("direct_%d_%d", min(m.SenderID, recipientID), max(m.SenderID, recipientID))
+}
+
+// Create a chat draft from a draft row.
+func newChatDraft(d db.ChatDraft) *model.ChatDraft {
+	return &model.ChatDraft{
+		ChatID:            d.ChatID,
+		Text:              d.TextContent,
+		ReplyForMessageID: d.ReplyForMessageID,
+		UpdatedAt:         d.UpdatedAt,
+	}
+}
+
+// Create chat settings from a chat settings row.
+func newChatSettings(cs db.ChatSetting) *model.ChatSettings {
+	return &model.ChatSettings{
+		ChatID:     cs.ChatID,
+		ArchivedAt: cs.ArchivedAt,
+		PinOrder:   cs.PinOrder,
+		MutedUntil: cs.MutedUntil,
+	}
+}
+
+// Create a user that is listed to other users e.g. in search results, the email is left out
+// since only the user themselves should see it.
+func newPublicUser(u db.GetBatchedUsersRow) *model.User {
+	return &model.User{
+		ID:          u.ID,
+		Username:    u.Username,
+		Name:        u.Name,
+		Bio:         u.Bio,
+		Image:       u.Image,
+		Online:      u.Online,
+		FriendCount: u.FriendCount,
+	}
+}
+
+// Escape the LIKE wildcards and the escape character in the value so it is matched literally.
+func escapeLikePattern(value string) string {
+	return likePatternReplacer.Replace(value)
+}
+
+// Get the trimmed search with the LIKE wildcards escaped, nil when the search is empty.
+func newLikeSearch(search *string) *string {
+	if search == nil {
+		return nil
+	}
+
+	trimmed := strings.TrimSpace(*search)
+	if trimmed == "" {
+		return nil
+	}
+
+	escaped := escapeLikePattern(trimmed)
+
+	return &escaped
+}
+
+var likePatternReplacer = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
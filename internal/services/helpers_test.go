@@ -0,0 +1,154 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+
+	"github.com/thanishsid/dingilink-server/internal/db"
+)
+
+func TestParseChatID(t *testing.T) {
+	tests := []struct {
+		chatID   string
+		wantType string
+		wantID   int64
+		wantErr  bool
+	}{
+		{"direct_1", "direct", 1, false},
+		{"group_42", "group", 42, false},
+		{"channel_1", "", 0, true},
+		{"direct", "", 0, true},
+		{"direct_", "", 0, true},
+		{"direct_abc", "", 0, true},
+		{"direct_1_2", "", 0, true},
+		{"", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.chatID, func(t *testing.T) {
+			idType, id, err := parseChatID(tt.chatID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseChatID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if idType != tt.wantType || id != tt.wantID {
+				t.Errorf("parseChatID() = %s, %d, want %s, %d", idType, id, tt.wantType, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestParseChatCursor(t *testing.T) {
+	tests := []struct {
+		cursor        string
+		wantPinOrder  int32
+		wantMessageID int64
+		wantErr       bool
+	}{
+		{"1:25", 1, 25, false},
+		{"2147483647:9", 2147483647, 9, false},
+		{"25", 0, 0, true},
+		{"a:25", 0, 0, true},
+		{"1:b", 0, 0, true},
+		{"2147483648:25", 0, 0, true},
+		{":", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cursor, func(t *testing.T) {
+			pinOrder, messageID, err := parseChatCursor(tt.cursor)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseChatCursor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if pinOrder != tt.wantPinOrder || messageID == nil || *messageID != tt.wantMessageID {
+				t.Errorf("parseChatCursor() = %d, %v, want %d, %d", pinOrder, messageID, tt.wantPinOrder, tt.wantMessageID)
+			}
+		})
+	}
+}
+
+func TestNewLikeSearch(t *testing.T) {
+	tests := []struct {
+		name   string
+		search *string
+		want   *string
+	}{
+		{"nil", nil, nil},
+		{"blank", null.StringFrom("   ").Ptr(), nil},
+		{"trimmed", null.StringFrom(" jane ").Ptr(), null.StringFrom("jane").Ptr()},
+		{"wildcards only", null.StringFrom("%_").Ptr(), null.StringFrom(`\%\_`).Ptr()},
+		{"escape character", null.StringFrom(`a\b`).Ptr(), null.StringFrom(`a\\b`).Ptr()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newLikeSearch(tt.search)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("newLikeSearch() = %v, want %v", null.StringFromPtr(got), null.StringFromPtr(tt.want))
+			}
+		})
+	}
+}
+
+func TestConversationKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		message db.Message
+		want    string
+	}{
+		{"group", db.Message{SenderID: 3, GroupID: null.IntFrom(7).Ptr()}, "group_7"},
+		{"direct from lower id", db.Message{SenderID: 3, RecipientID: null.IntFrom(9).Ptr()}, "direct_3_9"},
+		{"direct from higher id", db.Message{SenderID: 9, RecipientID: null.IntFrom(3).Ptr()}, "direct_3_9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := conversationKey(tt.message); got != tt.want {
+				t.Errorf("conversationKey() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGroupMemberCursor(t *testing.T) {
+	joinedAt := time.Date(2024, 5, 1, 10, 30, 0, 123000, time.UTC)
+
+	tests := []struct {
+		cursor       string
+		wantJoinedAt time.Time
+		wantMemberID int64
+		wantErr      bool
+	}{
+		{fmt.Sprintf("%d:%d", joinedAt.UnixMicro(), 12), joinedAt, 12, false},
+		{"0:1", time.UnixMicro(0), 1, false},
+		{"12", time.Time{}, 0, true},
+		{"a:12", time.Time{}, 0, true},
+		{"1714559400000123:b", time.Time{}, 0, true},
+		{":", time.Time{}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cursor, func(t *testing.T) {
+			gotJoinedAt, memberID, err := parseGroupMemberCursor(tt.cursor)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseGroupMemberCursor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if !gotJoinedAt.Valid || !gotJoinedAt.Time.Equal(tt.wantJoinedAt) || memberID == nil || *memberID != tt.wantMemberID {
+				t.Errorf("parseGroupMemberCursor() = %v, %v, want %v, %d", gotJoinedAt.Time, memberID, tt.wantJoinedAt, tt.wantMemberID)
+			}
+		})
+	}
+}
@@ -2,12 +2,19 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
-	"strconv"
+	"math"
 	"strings"
+	"time"
 
 	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"gopkg.in/guregu/null.v4"
 	"gopkg.in/typ.v4/slices"
 
@@ -20,88 +27,275 @@ import (
 )
 
 type MessageService struct {
-	DB db.DBQ
-	CH *messaging.ChannelManager[*model.MessageEvent]
+	DB         db.DBQ
+	CH         *messaging.ChannelManager[*model.MessageEvent]
+	DraftCH    *messaging.ChannelManager[*model.DraftEvent]
+	ChatListCH *messaging.ChannelManager[*model.ChatListEvent]
 }
 
 func getMessageChannelID(userID int64) string {
 	return fmt.Sprintf("user_%d.message_events", userID)
 }
 
-// Subscribe to message events
-func (s *MessageService) SubscribeToMessageEvents(ctx context.Context) (<-chan *model.MessageEvent, error) {
+func getDraftChannelID(userID int64) string {
+	return fmt.Sprintf("user_%d.draft_events", userID)
+}
+
+func getChatListChannelID(userID int64) string {
+	return fmt.Sprintf("user_%d.chat_list_events", userID)
+}
+
+// Number of logged events loaded at a time while replaying missed message events.
+const messageEventsReplayBatchSize = 100
+
+// Subscribe to message events, when a chat id is given only the events of the chat are received.
+// When since is given the logged events after it are replayed before switching to live events.
+func (s *MessageService) SubscribeToMessageEvents(ctx context.Context, chatID *string, since *string) (<-chan *model.MessageEvent, error) {
 	userInfo, err := security.Authorize(ctx, security.User)
 	if err != nil {
 		return nil, err
 	}
 
+	if chatID != nil {
+		if err := s.authorizeChatByID(ctx, userInfo.User.ID, *chatID); err != nil {
+			return nil, err
+		}
+	}
+
+	sinceID, err := parseCursor(since)
+	if err != nil {
+		return nil, vd.Errors{"since": errors.New(apperror.INPUT_INVALID)}
+	}
+
 	channelID := getMessageChannelID(userInfo.User.ID)
 
-	ch, err := s.CH.Subscribe(channelID)
+	// Subscribe before replaying so that no events are lost between the replay and the live events.
+	clientID, ch, err := s.CH.Subscribe(channelID)
 	if err != nil {
 		return nil, err
 	}
 
 	go func() {
 		<-ctx.Done()
-		s.CH.Unsubscribe(channelID)
+		s.CH.Unsubscribe(clientID)
 	}()
 
-	return ch, nil
+	if chatID == nil && sinceID == nil {
+		return ch, nil
+	}
+
+	events := make(chan *model.MessageEvent)
+
+	go func() {
+		defer close(events)
+
+		send := func(event *model.MessageEvent) bool {
+			select {
+			case events <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		// Ids of the replayed events, event ids are not committed in order so a live event can have a lower id
+		// than the last replayed event and only the replayed events themselves are skipped.
+		replayed := make(map[int64]struct{})
+
+		if sinceID != nil {
+			lastEventID := *sinceID
+
+			for {
+				logged, err := s.DB.GetMessageEventsAfter(ctx, db.GetMessageEventsAfterParams{
+					UserID:      userInfo.User.ID,
+					AfterID:     lastEventID,
+					ChatID:      chatID,
+					ResultLimit: messageEventsReplayBatchSize,
+				})
+				if err != nil {
+					log.Printf("failed to replay message events: %v", err)
+					break
+				}
+
+				for _, le := range logged {
+					lastEventID = le.ID
+					replayed[le.ID] = struct{}{}
+
+					var event model.MessageEvent
+
+					if err := json.Unmarshal(le.Payload, &event); err != nil {
+						log.Printf("failed to deserialize logged message event: %v", err)
+						continue
+					}
+
+					event.EventID = le.ID
+
+					if !send(&event) {
+						return
+					}
+				}
+
+				if len(logged) < messageEventsReplayBatchSize {
+					break
+				}
+			}
+		}
+
+		for {
+			select {
+			case event, ok := <-ch:
+				if !ok {
+					return
+				}
+
+				// Skip events that were already replayed and the events of other chats.
+				if _, ok := replayed[event.EventID]; ok {
+					delete(replayed, event.EventID)
+					continue
+				}
+
+				if chatID != nil && event.ChatID != *chatID {
+					continue
+				}
+
+				if !send(event) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+const (
+	defaultChatsPageSize int64 = 30
+	maxChatsPageSize     int64 = 100
+
+	// Sort key of unpinned chats, places them after all pinned chats.
+	unpinnedChatOrder int32 = math.MaxInt32
+)
+
+// GetChatsInput selects a page of chats, pinned chats come first followed by the rest ordered by last activity.
+type GetChatsInput struct {
+	First      *int64          `json:"first"`
+	After      *string         `json:"after"`
+	Archived   *bool           `json:"archived"`
+	Search     *string         `json:"search"`
+	UnreadOnly *bool           `json:"unreadOnly"`
+	Type       *model.ChatType `json:"type"`
+}
+
+func (i GetChatsInput) Validate() error {
+	return vd.ValidateStruct(&i,
+		vd.Field(&i.First,
+			vd.Min(int64(1)).Error(apperror.INPUT_TOO_LOW),
+			vd.Max(maxChatsPageSize).Error(apperror.INPUT_TOO_HIGH),
+		),
+		vd.Field(&i.Type, vd.In(model.ChatType(model.ChatTypeDirect), model.ChatType(model.ChatTypeGroup)).Error(apperror.INPUT_INVALID)),
+	)
 }
 
-// Get all user chats
-func (s *MessageService) GetChats(ctx context.Context) ([]model.ChatPreview, error) {
+// Get a page of the user's chats.
+func (s *MessageService) GetChats(ctx context.Context, input GetChatsInput) (*model.ChatPreviewConnection, error) {
 	userInfo, err := security.Authorize(ctx, security.User)
 	if err != nil {
 		return nil, err
 	}
 
-	chatsResult, err := s.DB.GetChats(ctx, userInfo.User.ID)
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Archived chats are only listed when explicitly requested.
+	archived := null.BoolFromPtr(input.Archived).ValueOrZero()
+
+	params := db.GetChatsParams{
+		UserID:      userInfo.User.ID,
+		Archived:    &archived,
+		UnreadOnly:  null.BoolFromPtr(input.UnreadOnly).ValueOrZero(),
+		ResultLimit: defaultChatsPageSize,
+	}
+
+	if input.First != nil {
+		params.ResultLimit = *input.First
+	}
+
+	params.Search = newLikeSearch(input.Search)
+
+	if input.Type != nil {
+		params.IsGroupChat = null.BoolFrom(*input.Type == model.ChatTypeGroup).Ptr()
+	}
+
+	if input.After != nil {
+		params.CursorPinOrder, params.CursorMessageID, err = parseChatCursor(*input.After)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	limit := params.ResultLimit
+
+	// One extra row is requested to find out if there are more chats.
+	params.ResultLimit++
+
+	chatsResult, err := s.DB.GetChats(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 
-	chats := make([]model.ChatPreview, len(chatsResult))
+	hasNextPage := int64(len(chatsResult)) > limit
+	if hasNextPage {
+		chatsResult = chatsResult[:limit]
+	}
+
+	edges := make([]model.ChatPreviewEdge, len(chatsResult))
 
 	for idx, c := range chatsResult {
-		var chat model.ChatPreview
+		pinOrder := unpinnedChatOrder
+		if c.PinOrder != nil {
+			pinOrder = *c.PinOrder
+		}
 
-		if c.IsGroupChat {
-			chat = model.GroupChatPreview{
-				GroupID:            null.IntFromPtr(c.ChatID).ValueOrZero(),
-				LastMessageID:      c.LastMessageID,
-				UnreadMessageCount: c.UnreadMessageCount,
-			}
-		} else {
-			chat = model.DirectChatPreview{
-				UserID:             null.IntFromPtr(c.ChatID).ValueOrZero(),
-				LastMessageID:      c.LastMessageID,
-				UnreadMessageCount: c.UnreadMessageCount,
-			}
+		edges[idx] = model.ChatPreviewEdge{
+			Node:   newChatPreview(c),
+			Cursor: fmt.Sprintf("%d:%d", pinOrder, c.LastMessageID),
 		}
+	}
+
+	connection := model.ChatPreviewConnection{
+		Edges: edges,
+		PageInfo: model.PageInfo{
+			HasNextPage:     hasNextPage,
+			HasPreviousPage: input.After != nil,
+		},
+	}
 
-		chats[idx] = chat
+	if len(edges) > 0 {
+		connection.PageInfo.StartCursor = &edges[0].Cursor
+		connection.PageInfo.EndCursor = &edges[len(edges)-1].Cursor
 	}
 
-	return chats, nil
+	return &connection, nil
 }
 
 // Get a chat
 func (s *MessageService) GetChat(ctx context.Context, chatID string) (model.Chat, error) {
-	_, err := security.Authorize(ctx, security.User)
+	userInfo, err := security.Authorize(ctx, security.User)
 	if err != nil {
 		return nil, err
 	}
 
-	splitId := strings.Split(chatID, "_")
-	if len(splitId) != 2 {
-		return nil, fmt.Errorf("invalid id")
+	idType, id, err := parseChatID(chatID)
+	if err != nil {
+		return nil, err
 	}
 
-	idType := splitId[0]
-
-	id := parseID(splitId[1])
+	if err := authorizeChatRead(ctx, s.DB, userInfo.User.ID, idType, id); err != nil {
+		return nil, err
+	}
 
 	switch idType {
 	case "group":
@@ -117,9 +311,62 @@ func (s *MessageService) GetChat(ctx context.Context, chatID string) (model.Chat
 	return nil, fmt.Errorf("invalid id")
 }
 
+// Get a message of a chat the current user is a participant of.
+func (s *MessageService) GetMessage(ctx context.Context, messageID int64) (model.Message, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := s.DB.GetMessageByID(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeMessageAccess(ctx, s.DB, userInfo.User.ID, m); err != nil {
+		return nil, err
+	}
+
+	return newMessageBuilder(m).Build()
+}
+
+const (
+	defaultMessagesPageSize int64 = 30
+	maxMessagesPageSize     int64 = 100
+)
+
+// GetMessagesInput selects a page of messages in a chat. Edges are always ordered from the newest to the oldest message,
+// so the next page holds older messages and the previous page holds newer ones.
+//
+//   - last / before pages backwards into older history (the default).
+//   - first / after pages forwards towards the newest message, first without after starts at the newest message.
+//   - around opens the chat at a message and returns messages on both sides of it.
 type GetMessagesInput struct {
+	First  *int64
+	After  *string
 	Last   *int64
 	Before *string
+	Around *int64
+}
+
+func (i GetMessagesInput) Validate() error {
+	return vd.ValidateStruct(&i,
+		vd.Field(&i.First,
+			vd.Nil.When(!vd.IsEmpty(i.Last)).Error(apperror.INPUT_NOT_REQUIRED),
+			vd.NilOrNotEmpty.Error(apperror.INPUT_TOO_LOW),
+			vd.Min(int64(1)).Error(apperror.INPUT_TOO_LOW),
+			vd.Max(maxMessagesPageSize).Error(apperror.INPUT_TOO_HIGH),
+		),
+		vd.Field(&i.After, vd.Nil.When(!vd.IsEmpty(i.Before) || !vd.IsEmpty(i.Around)).Error(apperror.INPUT_NOT_REQUIRED)),
+		vd.Field(&i.Last,
+			vd.Nil.When(!vd.IsEmpty(i.First)).Error(apperror.INPUT_NOT_REQUIRED),
+			vd.NilOrNotEmpty.Error(apperror.INPUT_TOO_LOW),
+			vd.Min(int64(1)).Error(apperror.INPUT_TOO_LOW),
+			vd.Max(maxMessagesPageSize).Error(apperror.INPUT_TOO_HIGH),
+		),
+		vd.Field(&i.Before, vd.Nil.When(!vd.IsEmpty(i.After) || !vd.IsEmpty(i.Around)).Error(apperror.INPUT_NOT_REQUIRED)),
+		vd.Field(&i.Around, vd.Nil.When(!vd.IsEmpty(i.After) || !vd.IsEmpty(i.Before)).Error(apperror.INPUT_NOT_REQUIRED)),
+	)
 }
 
 func (s *MessageService) GetMessages(ctx context.Context, chatID string, input GetMessagesInput) (*model.MessageConnection, error) {
@@ -128,59 +375,140 @@ func (s *MessageService) GetMessages(ctx context.Context, chatID string, input G
 		return nil, err
 	}
 
-	splitId := strings.Split(chatID, "_")
-	if len(splitId) != 2 {
-		return nil, fmt.Errorf("invalid id")
+	if err := input.Validate(); err != nil {
+		return nil, err
 	}
 
-	idType := splitId[0]
+	idType, id, err := parseChatID(chatID)
+	if err != nil {
+		return nil, err
+	}
 
-	id := parseID(splitId[1])
+	if err := authorizeChatRead(ctx, s.DB, userInfo.User.ID, idType, id); err != nil {
+		return nil, err
+	}
 
-	var cursorID *int64
-	var limit int64 = 30
+	targetUserID := null.NewInt(id, idType == "direct").Ptr()
+	targetGroupID := null.NewInt(id, idType == "group").Ptr()
 
-	if input.Before != nil {
-		cID, err := strconv.ParseInt(*input.Before, 10, 64)
-		if err != nil {
-			return nil, err
-		}
+	limit := defaultMessagesPageSize
 
-		cursorID = &cID
+	if input.First != nil {
+		limit = *input.First
 	}
 
 	if input.Last != nil {
 		limit = *input.Last
 	}
 
-	messagesResult, err := s.DB.GetMessages(ctx, db.GetMessagesParams{
-		TargetUserID:  null.NewInt(id, idType == "direct").Ptr(),
-		TargetGroupID: null.NewInt(id, idType == "group").Ptr(),
-		CurrentUserID: userInfo.User.ID,
-		CursorID:      cursorID,
-		ResultLimit:   limit,
-	})
-	if err != nil {
-		return nil, err
+	// Fetch messages older than the cursor, newest first. One extra row is requested to find out if there are more.
+	getOlderMessages := func(cursorID *int64, limit int64) ([]db.Message, bool, error) {
+		res, err := s.DB.GetMessagesBefore(ctx, db.GetMessagesBeforeParams{
+			TargetUserID:  targetUserID,
+			TargetGroupID: targetGroupID,
+			CurrentUserID: userInfo.User.ID,
+			CursorID:      cursorID,
+			ResultLimit:   limit + 1,
+		})
+		if err != nil {
+			return nil, false, err
+		}
+
+		if int64(len(res)) > limit {
+			return res[:limit], true, nil
+		}
+
+		return res, false, nil
+	}
+
+	// Fetch messages newer than the cursor, newest first. One extra row is requested to find out if there are more.
+	getNewerMessages := func(cursorID *int64, limit int64) ([]db.Message, bool, error) {
+		res, err := s.DB.GetMessagesAfter(ctx, db.GetMessagesAfterParams{
+			TargetUserID:  targetUserID,
+			TargetGroupID: targetGroupID,
+			CurrentUserID: userInfo.User.ID,
+			CursorID:      cursorID,
+			ResultLimit:   limit + 1,
+		})
+		if err != nil {
+			return nil, false, err
+		}
+
+		hasMore := int64(len(res)) > limit
+		if hasMore {
+			res = res[:limit]
+		}
+
+		return reversed(res), hasMore, nil
+	}
+
+	var (
+		messagesResult  []db.Message
+		hasNextPage     bool
+		hasPreviousPage bool
+	)
+
+	switch {
+	case input.Around != nil:
+		newerLimit := limit / 2
+		olderLimit := limit - newerLimit
+
+		// The target message is included in the older half.
+		olderMessages, hasOlder, err := getOlderMessages(null.IntFrom(*input.Around+1).Ptr(), olderLimit)
+		if err != nil {
+			return nil, err
+		}
+
+		newerMessages, hasNewer, err := getNewerMessages(input.Around, newerLimit)
+		if err != nil {
+			return nil, err
+		}
+
+		messagesResult = append(newerMessages, olderMessages...)
+		hasNextPage = hasOlder
+		hasPreviousPage = hasNewer
+
+	case input.After != nil:
+		cursorID, err := parseCursor(input.After)
+		if err != nil {
+			return nil, err
+		}
+
+		messagesResult, hasPreviousPage, err = getNewerMessages(cursorID, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		// The cursor message and the messages older than it are on the next page.
+		_, hasNextPage, err = getOlderMessages(null.IntFrom(*cursorID+1).Ptr(), 0)
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		cursorID, err := parseCursor(input.Before)
+		if err != nil {
+			return nil, err
+		}
+
+		messagesResult, hasNextPage, err = getOlderMessages(cursorID, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		if cursorID != nil {
+			// The cursor message and the messages newer than it are on the previous page.
+			_, hasPreviousPage, err = getNewerMessages(null.IntFrom(*cursorID-1).Ptr(), 0)
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	edges := make([]model.MessageEdge, len(messagesResult))
 
 	for idx, m := range messagesResult {
-		msg, err := model.MessageBuilder{
-			ID:                m.ID,
-			MessageType:       m.MessageType,
-			SenderID:          m.SenderID,
-			RecipientID:       m.RecipientID,
-			GroupID:           m.GroupID,
-			TextContent:       m.TextContent,
-			Media:             m.Media,
-			Location:          m.Location,
-			ReplyForMessageID: m.ReplyForMessageID,
-			SentAt:            m.SentAt,
-			DeletedAt:         m.DeletedAt,
-			DeletedBy:         m.DeletedBy,
-		}.Build()
+		msg, err := newMessageBuilder(m).Build()
 		if err != nil {
 			return nil, err
 		}
@@ -194,33 +522,15 @@ func (s *MessageService) GetMessages(ctx context.Context, chatID string, input G
 	}
 
 	connection := model.MessageConnection{
-		Edges:    edges,
-		PageInfo: model.PageInfo{},
+		Edges: edges,
+		PageInfo: model.PageInfo{
+			HasNextPage:     hasNextPage,
+			HasPreviousPage: hasPreviousPage,
+		},
 	}
 
 	if len(edges) > 0 {
-		hasNextPage, err := s.DB.CheckMessagesHasNextPage(ctx, db.CheckMessagesHasNextPageParams{
-			TargetUserID:  null.NewInt(id, idType == "direct").Ptr(),
-			TargetGroupID: null.NewInt(id, idType == "group").Ptr(),
-			CurrentUserID: userInfo.User.ID,
-			CursorID:      edges[len(edges)-1].Node.GetID(),
-		})
-		if err != nil {
-			return nil, err
-		}
-
-		hasPreviousPage, err := s.DB.CheckMessagesHasPreviousPage(ctx, db.CheckMessagesHasPreviousPageParams{
-			TargetUserID:  null.NewInt(id, idType == "direct").Ptr(),
-			TargetGroupID: null.NewInt(id, idType == "group").Ptr(),
-			CurrentUserID: userInfo.User.ID,
-			CursorID:      edges[0].Node.GetID(),
-		})
-		if err != nil {
-			return nil, err
-		}
-
-		connection.PageInfo.HasNextPage = hasNextPage
-		connection.PageInfo.HasPreviousPage = hasPreviousPage
+		connection.PageInfo.StartCursor = &edges[0].Cursor
 		connection.PageInfo.EndCursor = &edges[len(edges)-1].Cursor
 	}
 
@@ -235,6 +545,8 @@ type SendMessageInput struct {
 	Media             *string           `json:"media"`
 	Location          *types.LatLng     `json:"location"`
 	ReplyForMessageID *int64            `json:"replyForMessageId"`
+	ClientMessageID   *string           `json:"clientMessageId"`
+	Poll              *PollInput        `json:"poll"`
 }
 
 func (i SendMessageInput) Validate() error {
@@ -246,6 +558,7 @@ func (i SendMessageInput) Validate() error {
 		model.MessageTypeVideo,
 		model.MessageTypeDocument,
 		model.MessageTypeLocation,
+		model.MessageTypePoll,
 	}, func(mt model.MessageType) any {
 		return mt
 	})
@@ -272,6 +585,36 @@ func (i SendMessageInput) Validate() error {
 			model.MessageTypeDocument,
 		}, i.Type)).Error(apperror.INPUT_REQUIRED)),
 		vd.Field(&i.Location, vd.Required.When(i.Type == model.MessageTypeLocation).Error(apperror.INPUT_REQUIRED)),
+		vd.Field(&i.ClientMessageID, is.UUID.Error(apperror.INPUT_INVALID)),
+		vd.Field(&i.Poll,
+			vd.Required.When(i.Type == model.MessageTypePoll).Error(apperror.INPUT_REQUIRED),
+			vd.Nil.When(i.Type != model.MessageTypePoll).Error(apperror.INPUT_NOT_REQUIRED),
+		),
+	)
+}
+
+const (
+	minPollOptions = 2
+	maxPollOptions = 12
+)
+
+type PollInput struct {
+	Question       string     `json:"question"`
+	Options        []string   `json:"options"`
+	MultipleChoice bool       `json:"multipleChoice"`
+	Anonymous      bool       `json:"anonymous"`
+	ClosesAt       *time.Time `json:"closesAt"`
+}
+
+func (i PollInput) Validate() error {
+	return vd.ValidateStruct(&i,
+		vd.Field(&i.Question, vd.Required.Error(apperror.INPUT_REQUIRED)),
+		vd.Field(&i.Options,
+			vd.Required.Error(apperror.INPUT_REQUIRED),
+			vd.Length(minPollOptions, maxPollOptions).Error(apperror.INPUT_INVALID),
+			vd.Each(vd.Required.Error(apperror.INPUT_REQUIRED)),
+		),
+		vd.Field(&i.ClosesAt, vd.Min(time.Now()).Error(apperror.INPUT_TOO_LOW)),
 	)
 }
 
@@ -286,7 +629,39 @@ func (s *MessageService) SendMessage(ctx context.Context, input SendMessageInput
 		return nil, err
 	}
 
-	m, err := s.DB.InsertMessage(ctx, db.InsertMessageParams{
+	idType, id := "direct", null.IntFromPtr(input.UserID).ValueOrZero()
+	if input.GroupID != nil {
+		idType, id = "group", *input.GroupID
+	}
+
+	if err := authorizeChatWrite(ctx, s.DB, userInfo.User.ID, idType, id); err != nil {
+		return nil, err
+	}
+
+	if input.ReplyForMessageID != nil {
+		if err := validateReplyMessage(ctx, s.DB, userInfo.User.ID, idType, id, *input.ReplyForMessageID); err != nil {
+			return nil, err
+		}
+	}
+
+	var clientMessageID uuid.NullUUID
+
+	if input.ClientMessageID != nil {
+		clientMessageID.UUID, err = uuid.Parse(*input.ClientMessageID)
+		if err != nil {
+			return nil, err
+		}
+
+		clientMessageID.Valid = true
+	}
+
+	tx, err := s.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	m, err := tx.InsertMessage(ctx, db.InsertMessageParams{
 		SenderID:          userInfo.User.ID,
 		RecipientID:       input.UserID,
 		GroupID:           input.GroupID,
@@ -295,61 +670,880 @@ func (s *MessageService) SendMessage(ctx context.Context, input SendMessageInput
 		Media:             input.Media,
 		Location:          types.LatLngToPoint(input.Location),
 		ReplyForMessageID: input.ReplyForMessageID,
+		ClientMessageID:   clientMessageID,
 	})
-	if err != nil {
-		return nil, err
-	}
+	if errors.Is(err, pgx.ErrNoRows) && clientMessageID.Valid {
+		// The message was already stored by an earlier attempt, return the original message without sending events again.
+		m, err = tx.GetMessageByClientMessageID(ctx, db.GetMessageByClientMessageIDParams{
+			SenderID:        userInfo.User.ID,
+			ClientMessageID: clientMessageID,
+		})
+		if err != nil {
+			return nil, err
+		}
 
-	msgb := model.MessageBuilder{
-		ID:                m.ID,
-		SenderID:          m.SenderID,
-		RecipientID:       m.RecipientID,
-		GroupID:           m.GroupID,
-		MessageType:       m.MessageType,
-		TextContent:       m.TextContent,
-		Media:             m.Media,
-		Location:          m.Location,
-		ReplyForMessageID: m.ReplyForMessageID,
-		SentAt:            m.SentAt,
-		DeletedAt:         m.DeletedAt,
-		DeletedBy:         m.DeletedBy,
+		return newMessageBuilder(m).Build()
 	}
-
-	isDirect := m.RecipientID != nil
-	isGroup := m.GroupID != nil
-
-	messageEvent := model.MessageEvent{
-		Type:      model.MessageEventTypeNew,
-		MessageID: m.ID,
+	if err != nil {
+		return nil, err
 	}
 
-	go func() {
-		// If direct message send an event through the channel manager to the recipient
-		if isDirect {
-			if err := s.CH.SendPayload(getMessageChannelID(null.IntFromPtr(m.RecipientID).ValueOrZero()), &messageEvent); err != nil {
-				log.Printf("failed to send direct message event via channel manager: %v", err)
-			}
+	if input.Poll != nil {
+		poll, err := tx.InsertPoll(ctx, db.InsertPollParams{
+			MessageID:      m.ID,
+			Question:       input.Poll.Question,
+			MultipleChoice: input.Poll.MultipleChoice,
+			Anonymous:      input.Poll.Anonymous,
+			ClosesAt: pgtype.Timestamptz{
+				Time:  null.TimeFromPtr(input.Poll.ClosesAt).ValueOrZero(),
+				Valid: input.Poll.ClosesAt != nil,
+			},
+		})
+		if err != nil {
+			return nil, err
 		}
 
-		// If group message send an event through the channel manager to all group members
-		if isGroup {
-			members, err := s.DB.GetGroupMembers(ctx, *m.GroupID)
-			if err != nil {
-				return
-			}
-
-			for _, mb := range members {
-				if err := s.CH.SendPayload(getMessageChannelID(mb.UserID), &messageEvent); err != nil {
-					log.Printf("failed to send group message event via channel manager: %v", err)
-				}
+		for idx, option := range input.Poll.Options {
+			if err := tx.InsertPollOption(ctx, db.InsertPollOptionParams{
+				PollID:     poll.ID,
+				OptionText: option,
+				SortIndex:  int32(idx),
+			}); err != nil {
+				return nil, err
 			}
 		}
-	}()
+	}
 
-	msg, err := msgb.Build()
+	conversation, err := recordConversationMessage(ctx, tx, m)
 	if err != nil {
 		return nil, err
 	}
 
-	return msg, nil
+	msgb := newMessageBuilder(m)
+
+	chatID := fmt.Sprintf("direct_%d", null.IntFromPtr(m.RecipientID).ValueOrZero())
+	if m.GroupID != nil {
+		chatID = fmt.Sprintf("group_%d", *m.GroupID)
+	}
+
+	// Sending a message to a chat clears the draft of the chat.
+	clearedDrafts, err := tx.DeleteChatDraft(ctx, db.DeleteChatDraftParams{
+		UserID: userInfo.User.ID,
+		ChatID: chatID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	if clearedDrafts > 0 {
+		s.sendDraftEvent(userInfo.User.ID, &model.DraftEvent{
+			Type:   model.DraftEventTypeCleared,
+			ChatID: chatID,
+		})
+	}
+
+	s.sendMessageEvent(ctx, m, &model.MessageEvent{
+		Type:            model.MessageEventTypeNew,
+		MessageID:       m.ID,
+		ClientMessageID: input.ClientMessageID,
+		Sequence:        conversation.EventSequence,
+	})
+
+	msg, err := msgb.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+type MuteChatInput struct {
+	ChatID string    `json:"chatId"`
+	Until  time.Time `json:"until"`
+}
+
+func (i MuteChatInput) Validate() error {
+	return vd.ValidateStruct(&i,
+		vd.Field(&i.ChatID, vd.Required.Error(apperror.INPUT_REQUIRED), vd.By(validateChatID)),
+		vd.Field(&i.Until,
+			vd.Required.Error(apperror.INPUT_REQUIRED),
+			vd.Min(time.Now()).Error(apperror.INPUT_TOO_LOW),
+		),
+	)
+}
+
+// Archive or unarchive a chat for the current user.
+func (s *MessageService) SetChatArchived(ctx context.Context, chatID string, archived bool) (*model.ChatSettings, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizeChatByID(ctx, userInfo.User.ID, chatID); err != nil {
+		return nil, err
+	}
+
+	cs, err := s.DB.SetChatArchived(ctx, db.SetChatArchivedParams{
+		UserID:   userInfo.User.ID,
+		ChatID:   chatID,
+		Archived: archived,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go s.sendChatPreviewEvent(userInfo.User.ID, cs.ChatID)
+
+	return newChatSettings(cs), nil
+}
+
+// Pin a chat to the top of the chat list of the current user, the chat is placed after the already pinned chats.
+func (s *MessageService) PinChat(ctx context.Context, chatID string) (*model.ChatSettings, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizeChatByID(ctx, userInfo.User.ID, chatID); err != nil {
+		return nil, err
+	}
+
+	cs, err := s.DB.PinChat(ctx, db.PinChatParams{
+		UserID: userInfo.User.ID,
+		ChatID: chatID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go s.sendChatPreviewEvent(userInfo.User.ID, cs.ChatID)
+
+	return newChatSettings(cs), nil
+}
+
+// Unpin a chat of the current user.
+func (s *MessageService) UnpinChat(ctx context.Context, chatID string) (*model.ChatSettings, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizeChatByID(ctx, userInfo.User.ID, chatID); err != nil {
+		return nil, err
+	}
+
+	cs, err := s.DB.UnpinChat(ctx, db.UnpinChatParams{
+		UserID: userInfo.User.ID,
+		ChatID: chatID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go s.sendChatPreviewEvent(userInfo.User.ID, cs.ChatID)
+
+	return newChatSettings(cs), nil
+}
+
+// Mute the notifications of a chat until the given time.
+func (s *MessageService) MuteChat(ctx context.Context, input MuteChatInput) (*model.ChatSettings, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizeChatByID(ctx, userInfo.User.ID, input.ChatID); err != nil {
+		return nil, err
+	}
+
+	cs, err := s.DB.SetChatMutedUntil(ctx, db.SetChatMutedUntilParams{
+		UserID:     userInfo.User.ID,
+		ChatID:     input.ChatID,
+		MutedUntil: pgtype.Timestamptz{Time: input.Until, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go s.sendChatPreviewEvent(userInfo.User.ID, cs.ChatID)
+
+	return newChatSettings(cs), nil
+}
+
+// Unmute the notifications of a chat.
+func (s *MessageService) UnmuteChat(ctx context.Context, chatID string) (*model.ChatSettings, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizeChatByID(ctx, userInfo.User.ID, chatID); err != nil {
+		return nil, err
+	}
+
+	cs, err := s.DB.SetChatMutedUntil(ctx, db.SetChatMutedUntilParams{
+		UserID: userInfo.User.ID,
+		ChatID: chatID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go s.sendChatPreviewEvent(userInfo.User.ID, cs.ChatID)
+
+	return newChatSettings(cs), nil
+}
+
+type SaveDraftInput struct {
+	ChatID           string `json:"chatId"`
+	Text             string `json:"text"`
+	ReplyToMessageID *int64 `json:"replyToMessageId"`
+}
+
+func (i SaveDraftInput) Validate() error {
+	return vd.ValidateStruct(&i,
+		vd.Field(&i.ChatID, vd.Required.Error(apperror.INPUT_REQUIRED), vd.By(validateChatID)),
+	)
+}
+
+// Save the draft of a chat, an empty text clears the draft.
+func (s *MessageService) SaveDraft(ctx context.Context, input SaveDraftInput) (*model.ChatDraft, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	idType, id, err := parseChatID(input.ChatID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeChatRead(ctx, s.DB, userInfo.User.ID, idType, id); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(input.Text) == "" {
+		if _, err := s.DB.DeleteChatDraft(ctx, db.DeleteChatDraftParams{
+			UserID: userInfo.User.ID,
+			ChatID: input.ChatID,
+		}); err != nil {
+			return nil, err
+		}
+
+		s.sendDraftEvent(userInfo.User.ID, &model.DraftEvent{
+			Type:   model.DraftEventTypeCleared,
+			ChatID: input.ChatID,
+		})
+
+		return nil, nil
+	}
+
+	if input.ReplyToMessageID != nil {
+		if err := validateReplyMessage(ctx, s.DB, userInfo.User.ID, idType, id, *input.ReplyToMessageID); err != nil {
+			return nil, err
+		}
+	}
+
+	d, err := s.DB.UpsertChatDraft(ctx, db.UpsertChatDraftParams{
+		UserID:            userInfo.User.ID,
+		ChatID:            input.ChatID,
+		TextContent:       input.Text,
+		ReplyForMessageID: input.ReplyToMessageID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	draft := newChatDraft(d)
+
+	s.sendDraftEvent(userInfo.User.ID, &model.DraftEvent{
+		Type:   model.DraftEventTypeUpdated,
+		ChatID: draft.ChatID,
+		Draft:  draft,
+	})
+
+	return draft, nil
+}
+
+type VoteInput struct {
+	MessageID int64   `json:"messageId"`
+	OptionIDs []int64 `json:"optionIds"`
+}
+
+func (i VoteInput) Validate() error {
+	return vd.ValidateStruct(&i,
+		vd.Field(&i.MessageID, vd.Required.Error(apperror.INPUT_REQUIRED)),
+		vd.Field(&i.OptionIDs,
+			vd.Required.Error(apperror.INPUT_REQUIRED),
+			vd.Length(1, maxPollOptions).Error(apperror.INPUT_INVALID),
+		),
+	)
+}
+
+// Vote in a poll, replaces any previous votes of the current user in the poll.
+func (s *MessageService) Vote(ctx context.Context, input VoteInput) (model.Message, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	m, poll, err := s.getVotablePoll(ctx, userInfo.User.ID, input.MessageID)
+	if err != nil {
+		return nil, err
+	}
+
+	optionIDs := slices.Distinct(input.OptionIDs)
+
+	if !poll.MultipleChoice && len(optionIDs) > 1 {
+		return nil, vd.Errors{"optionIds": errors.New(apperror.INPUT_TOO_HIGH)}
+	}
+
+	options, err := s.DB.GetBatchedPollOptions(ctx, []int64{poll.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range optionIDs {
+		if !slices.Any(options, func(o db.GetBatchedPollOptionsRow) bool { return o.ID == id }) {
+			return nil, vd.Errors{"optionIds": errors.New(apperror.INPUT_INVALID)}
+		}
+	}
+
+	tx, err := s.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Votes of the poll are replaced one transaction at a time, otherwise concurrent votes
+	// could both delete the previous votes and leave more than one option of a single choice poll.
+	if err := tx.LockPoll(ctx, poll.ID); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.DeletePollVotes(ctx, db.DeletePollVotesParams{
+		PollID: poll.ID,
+		UserID: userInfo.User.ID,
+	}); err != nil {
+		return nil, err
+	}
+
+	for _, id := range optionIDs {
+		if err := tx.InsertPollVote(ctx, db.InsertPollVoteParams{
+			PollID:       poll.ID,
+			PollOptionID: id,
+			UserID:       userInfo.User.ID,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	s.sendMessageEvent(ctx, m, &model.MessageEvent{
+		Type:      model.MessageEventTypePollUpdated,
+		MessageID: m.ID,
+	})
+
+	return newMessageBuilder(m).Build()
+}
+
+type RetractVoteInput struct {
+	MessageID int64 `json:"messageId"`
+}
+
+func (i RetractVoteInput) Validate() error {
+	return vd.ValidateStruct(&i,
+		vd.Field(&i.MessageID, vd.Required.Error(apperror.INPUT_REQUIRED)),
+	)
+}
+
+// Retract all votes of the current user in a poll.
+func (s *MessageService) RetractVote(ctx context.Context, input RetractVoteInput) (model.Message, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	m, poll, err := s.getVotablePoll(ctx, userInfo.User.ID, input.MessageID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	retracted, err := tx.DeletePollVotes(ctx, db.DeletePollVotesParams{
+		PollID: poll.ID,
+		UserID: userInfo.User.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	if retracted > 0 {
+		s.sendMessageEvent(ctx, m, &model.MessageEvent{
+			Type:      model.MessageEventTypePollUpdated,
+			MessageID: m.ID,
+		})
+	}
+
+	return newMessageBuilder(m).Build()
+}
+
+// Get a poll message and its poll, the user must be a participant of the chat and the poll must be open.
+func (s *MessageService) getVotablePoll(ctx context.Context, userID int64, messageID int64) (db.Message, db.Poll, error) {
+	m, err := s.DB.GetMessageByID(ctx, messageID)
+	if err != nil {
+		return db.Message{}, db.Poll{}, err
+	}
+
+	if err := authorizeMessageAccess(ctx, s.DB, userID, m); err != nil {
+		return db.Message{}, db.Poll{}, err
+	}
+
+	if m.DeletedAt.Valid {
+		return db.Message{}, db.Poll{}, apperror.ErrNotFound
+	}
+
+	poll, err := s.DB.GetPollByMessageID(ctx, m.ID)
+	if err != nil {
+		return db.Message{}, db.Poll{}, err
+	}
+
+	if poll.ClosesAt.Valid && !poll.ClosesAt.Time.After(time.Now()) {
+		return db.Message{}, db.Poll{}, apperror.ErrPollClosed
+	}
+
+	return m, poll, nil
+}
+
+type SystemMessageParams struct {
+	ActorID     int64
+	RecipientID *int64
+	GroupID     *int64
+	Event       model.SystemEvent
+}
+
+// Send a system message to a direct or group chat, used by other services to record chat and group lifecycle events.
+func (s *MessageService) SendSystemMessage(ctx context.Context, params SystemMessageParams) (model.Message, error) {
+	event, err := json.Marshal(params.Event)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	m, err := tx.InsertMessage(ctx, db.InsertMessageParams{
+		SenderID:    params.ActorID,
+		RecipientID: params.RecipientID,
+		GroupID:     params.GroupID,
+		MessageType: model.MessageTypeSystem,
+		SystemEvent: event,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	conversation, err := recordConversationMessage(ctx, tx, m)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	s.sendMessageEvent(ctx, m, &model.MessageEvent{
+		Type:      model.MessageEventTypeNew,
+		MessageID: m.ID,
+		Sequence:  conversation.EventSequence,
+	})
+
+	return newMessageBuilder(m).Build()
+}
+
+// Record a new message as the last message of its conversation and increase the unread counts of the other members,
+// the conversation and its members are created on the first message of a chat.
+func recordConversationMessage(ctx context.Context, q db.Querier, m db.Message) (db.Conversation, error) {
+	c, err := q.UpsertConversation(ctx, db.UpsertConversationParams{
+		ConversationKey: conversationKey(m),
+		GroupID:         m.GroupID,
+		LastMessageID:   m.ID,
+		LastActivityAt:  m.SentAt,
+	})
+	if err != nil {
+		return c, err
+	}
+
+	if m.GroupID != nil {
+		// Members joining later are added to the conversation when they join the group,
+		// existing members are added when the first message creates the conversation.
+		if c.EventSequence == 1 {
+			if err := q.InsertGroupConversationMembers(ctx, db.InsertGroupConversationMembersParams{
+				ConversationID: c.ID,
+				GroupID:        *m.GroupID,
+			}); err != nil {
+				return c, err
+			}
+		}
+	} else {
+		recipientID := null.IntFromPtr(m.RecipientID).ValueOrZero()
+
+		members := []db.UpsertConversationMemberParams{
+			{ConversationID: c.ID, UserID: m.SenderID, ChatID: fmt.Sprintf("direct_%d", recipientID), PeerUserID: &recipientID},
+			{ConversationID: c.ID, UserID: recipientID, ChatID: fmt.Sprintf("direct_%d", m.SenderID), PeerUserID: &m.SenderID},
+		}
+
+		for _, member := range members {
+			if err := q.UpsertConversationMember(ctx, member); err != nil {
+				return c, err
+			}
+		}
+	}
+
+	err = q.IncrementConversationUnreadCounts(ctx, db.IncrementConversationUnreadCountsParams{
+		ConversationID: c.ID,
+		SenderID:       m.SenderID,
+	})
+
+	return c, err
+}
+
+// Mark all messages of a chat as read by the current user and reset its unread count.
+func (s *MessageService) MarkChatAsRead(ctx context.Context, chatID string) error {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return err
+	}
+
+	idType, id, err := parseChatID(chatID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeChatRead(ctx, s.DB, userInfo.User.ID, idType, id); err != nil {
+		return err
+	}
+
+	member, err := s.DB.GetConversationMember(ctx, db.GetConversationMemberParams{
+		UserID: userInfo.User.ID,
+		ChatID: chatID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// The chat has no messages yet.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.InsertChatReadReceipts(ctx, db.InsertChatReadReceiptsParams{
+		UserID:         userInfo.User.ID,
+		TargetUserID:   null.NewInt(id, idType == "direct").Ptr(),
+		TargetGroupID:  null.NewInt(id, idType == "group").Ptr(),
+		AfterMessageID: member.LastReadMessageID,
+	}); err != nil {
+		return err
+	}
+
+	if _, err := tx.MarkConversationAsRead(ctx, db.MarkConversationAsReadParams{
+		UserID: userInfo.User.ID,
+		ChatID: chatID,
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	if member.UnreadCount > 0 {
+		go s.sendChatPreviewEvent(userInfo.User.ID, chatID)
+	}
+
+	return nil
+}
+
+// Send a message event to all participants of the chat the message belongs to, the event carries the message
+// so subscribers don't have to load it. Events without a sequence number are assigned the next number of the chat.
+// New and deleted messages also change the chat previews of the participants.
+func (s *MessageService) sendMessageEvent(ctx context.Context, m db.Message, event *model.MessageEvent) {
+	updatesPreview := event.Type == model.MessageEventTypeNew || event.Type == model.MessageEventTypeDeleted
+
+	// The event is sent after the request has completed.
+	ctx = context.WithoutCancel(ctx)
+
+	go func() {
+		if event.Sequence == 0 {
+			sequence, err := s.DB.IncrementConversationEventSequence(ctx, conversationKey(m))
+			if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+				log.Printf("failed to get message event sequence: %v", err)
+			}
+
+			event.Sequence = sequence
+		}
+
+		payload := newMessageBuilder(m)
+		event.Payload = &payload
+
+		// Chat ids of the receivers of the event.
+		receivers := make(map[int64]string)
+
+		// If direct message send the event to the recipient and to the sender's other devices
+		if m.RecipientID != nil {
+			receivers[*m.RecipientID] = fmt.Sprintf("direct_%d", m.SenderID)
+			receivers[m.SenderID] = fmt.Sprintf("direct_%d", *m.RecipientID)
+		}
+
+		// If group message send the event to all group members
+		if m.GroupID != nil {
+			members, err := s.DB.GetGroupMembers(ctx, *m.GroupID)
+			if err != nil {
+				log.Printf("failed to get group members for message event: %v", err)
+				return
+			}
+
+			for _, mb := range members {
+				receivers[mb.UserID] = fmt.Sprintf("group_%d", *m.GroupID)
+			}
+		}
+
+		receiverEvents := make(map[int64]*model.MessageEvent, len(receivers))
+
+		for userID, chatID := range receivers {
+			receiverEvent := *event
+			receiverEvent.ChatID = chatID
+			receiverEvents[userID] = &receiverEvent
+		}
+
+		// Store the events before sending them so they can be replayed to clients that miss them.
+		if err := s.logMessageEvents(ctx, receiverEvents); err != nil {
+			log.Printf("failed to log message events: %v", err)
+		}
+
+		for userID, receiverEvent := range receiverEvents {
+			if err := s.CH.SendPayload(getMessageChannelID(userID), receiverEvent); err != nil {
+				log.Printf("failed to send message event via channel manager: %v", err)
+			}
+		}
+
+		if !updatesPreview {
+			return
+		}
+
+		// Load the previews of all receivers at once instead of each subscriber loading its own preview.
+		previews, err := s.DB.GetConversationChatPreviews(ctx, conversationKey(m))
+		if err != nil {
+			log.Printf("failed to get chat previews for chat list events: %v", err)
+			return
+		}
+
+		for _, p := range previews {
+			if _, ok := receivers[p.UserID]; !ok {
+				continue
+			}
+
+			s.sendChatListEvent(p.UserID, &model.ChatListEvent{
+				ChatID: p.ChatID,
+				Preview: newChatPreviewPayload(db.GetChatsRow{
+					ChatID:                 p.ChatID,
+					TargetID:               p.TargetID,
+					IsGroupChat:            p.IsGroupChat,
+					LastMessageID:          p.LastMessageID,
+					UnreadCount:            p.UnreadCount,
+					DraftText:              p.DraftText,
+					DraftReplyForMessageID: p.DraftReplyForMessageID,
+					DraftUpdatedAt:         p.DraftUpdatedAt,
+					ArchivedAt:             p.ArchivedAt,
+					PinOrder:               p.PinOrder,
+					MutedUntil:             p.MutedUntil,
+				}),
+			})
+		}
+	}()
+}
+
+// Store message events in the events log of their receivers and set the ids of the events.
+func (s *MessageService) logMessageEvents(ctx context.Context, events map[int64]*model.MessageEvent) error {
+	params := db.InsertMessageEventsParams{
+		UserIds:  make([]int64, 0, len(events)),
+		ChatIds:  make([]string, 0, len(events)),
+		Payloads: make([][]byte, 0, len(events)),
+	}
+
+	for userID, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		params.UserIds = append(params.UserIds, userID)
+		params.ChatIds = append(params.ChatIds, event.ChatID)
+		params.Payloads = append(params.Payloads, payload)
+	}
+
+	rows, err := s.DB.InsertMessageEvents(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		events[row.UserID].EventID = row.ID
+	}
+
+	return nil
+}
+
+// Message events older than this are removed from the events log and can no longer be replayed.
+const messageEventRetention = time.Hour * 24 * 7
+
+// Periodically remove expired events from the message events log until the context is cancelled.
+func (s *MessageService) PruneMessageEvents(ctx context.Context) error {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		pruned, err := s.DB.DeleteMessageEventsBefore(ctx, pgtype.Timestamptz{
+			Time:  time.Now().Add(-messageEventRetention),
+			Valid: true,
+		})
+		if err != nil {
+			log.Printf("failed to prune message events: %v", err)
+		} else if pruned > 0 {
+			log.Printf("pruned %d expired message events", pruned)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Subscribe to chat preview changes of the current user.
+func (s *MessageService) SubscribeToChatListEvents(ctx context.Context) (<-chan *model.ChatListEvent, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	clientID, ch, err := s.ChatListCH.Subscribe(getChatListChannelID(userInfo.User.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		<-ctx.Done()
+		s.ChatListCH.Unsubscribe(clientID)
+	}()
+
+	return ch, nil
+}
+
+// Notify all devices of a user that the preview of a chat has changed.
+func (s *MessageService) sendChatListEvent(userID int64, event *model.ChatListEvent) {
+	if err := s.ChatListCH.SendPayload(getChatListChannelID(userID), event); err != nil {
+		log.Printf("failed to send chat list event via channel manager: %v", err)
+	}
+}
+
+// Load the latest preview of a chat of a user and send it to all devices of the user.
+func (s *MessageService) sendChatPreviewEvent(userID int64, chatID string) {
+	chatsResult, err := s.DB.GetChats(context.Background(), db.GetChatsParams{
+		UserID:      userID,
+		ChatID:      &chatID,
+		ResultLimit: 1,
+	})
+	if err != nil {
+		log.Printf("failed to get chat preview for chat list event: %v", err)
+		return
+	}
+
+	event := &model.ChatListEvent{ChatID: chatID}
+
+	if len(chatsResult) > 0 {
+		event.Preview = newChatPreviewPayload(chatsResult[0])
+	}
+
+	s.sendChatListEvent(userID, event)
+}
+
+// Parse a chat id and check if the user can read the chat.
+func (s *MessageService) authorizeChatByID(ctx context.Context, userID int64, chatID string) error {
+	idType, id, err := parseChatID(chatID)
+	if err != nil {
+		return err
+	}
+
+	return authorizeChatRead(ctx, s.DB, userID, idType, id)
+}
+
+// Subscribe to draft changes of the current user.
+func (s *MessageService) SubscribeToDraftEvents(ctx context.Context) (<-chan *model.DraftEvent, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	clientID, ch, err := s.DraftCH.Subscribe(getDraftChannelID(userInfo.User.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		<-ctx.Done()
+		s.DraftCH.Unsubscribe(clientID)
+	}()
+
+	return ch, nil
+}
+
+// Send a draft event to all devices of a user.
+func (s *MessageService) sendDraftEvent(userID int64, event *model.DraftEvent) {
+	go func() {
+		if err := s.DraftCH.SendPayload(getDraftChannelID(userID), event); err != nil {
+			log.Printf("failed to send draft event via channel manager: %v", err)
+		}
+	}()
 }
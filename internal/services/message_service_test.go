@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+
+	"github.com/thanishsid/dingilink-server/internal/db"
+	"github.com/thanishsid/dingilink-server/internal/model"
+)
+
+func TestGetMessagesInputValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   GetMessagesInput
+		wantErr bool
+	}{
+		{"empty", GetMessagesInput{}, false},
+		{"first", GetMessagesInput{First: null.IntFrom(10).Ptr()}, false},
+		{"first and after", GetMessagesInput{First: null.IntFrom(10).Ptr(), After: null.StringFrom("5").Ptr()}, false},
+		{"last and before", GetMessagesInput{Last: null.IntFrom(10).Ptr(), Before: null.StringFrom("5").Ptr()}, false},
+		{"around", GetMessagesInput{Around: null.IntFrom(5).Ptr()}, false},
+		{"first and last", GetMessagesInput{First: null.IntFrom(10).Ptr(), Last: null.IntFrom(10).Ptr()}, true},
+		{"after and before", GetMessagesInput{After: null.StringFrom("5").Ptr(), Before: null.StringFrom("9").Ptr()}, true},
+		{"around and after", GetMessagesInput{Around: null.IntFrom(5).Ptr(), After: null.StringFrom("5").Ptr()}, true},
+		{"first too low", GetMessagesInput{First: null.IntFrom(0).Ptr()}, true},
+		{"last too high", GetMessagesInput{Last: null.IntFrom(maxMessagesPageSize + 1).Ptr()}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.input.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSendMessageInputValidate(t *testing.T) {
+	text := null.StringFrom("hello").Ptr()
+
+	tests := []struct {
+		name    string
+		input   SendMessageInput
+		wantErr bool
+	}{
+		{"direct text", SendMessageInput{UserID: null.IntFrom(2).Ptr(), Type: model.MessageTypeText, Text: text}, false},
+		{"group text", SendMessageInput{GroupID: null.IntFrom(2).Ptr(), Type: model.MessageTypeText, Text: text}, false},
+		{"no target", SendMessageInput{Type: model.MessageTypeText, Text: text}, true},
+		{"both targets", SendMessageInput{UserID: null.IntFrom(2).Ptr(), GroupID: null.IntFrom(2).Ptr(), Type: model.MessageTypeText, Text: text}, true},
+		{"text without text", SendMessageInput{UserID: null.IntFrom(2).Ptr(), Type: model.MessageTypeText}, true},
+		{"image without media", SendMessageInput{UserID: null.IntFrom(2).Ptr(), Type: model.MessageTypeImage}, true},
+		{"valid client message id", SendMessageInput{
+			UserID:          null.IntFrom(2).Ptr(),
+			Type:            model.MessageTypeText,
+			Text:            text,
+			ClientMessageID: null.StringFrom("7c9e6679-7425-40de-944b-e07fc1f90ae7").Ptr(),
+		}, false},
+		{"invalid client message id", SendMessageInput{
+			UserID:          null.IntFrom(2).Ptr(),
+			Type:            model.MessageTypeText,
+			Text:            text,
+			ClientMessageID: null.StringFrom("not-a-uuid").Ptr(),
+		}, true},
+		{"poll on text message", SendMessageInput{
+			UserID: null.IntFrom(2).Ptr(),
+			Type:   model.MessageTypeText,
+			Text:   text,
+			Poll:   &PollInput{Question: "?", Options: []string{"a", "b"}},
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.input.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPollInputValidate(t *testing.T) {
+	tooManyOptions := make([]string, maxPollOptions+1)
+	for i := range tooManyOptions {
+		tooManyOptions[i] = "option"
+	}
+
+	tests := []struct {
+		name    string
+		input   PollInput
+		wantErr bool
+	}{
+		{"valid", PollInput{Question: "Lunch?", Options: []string{"Yes", "No"}}, false},
+		{"closes later", PollInput{Question: "Lunch?", Options: []string{"Yes", "No"}, ClosesAt: null.TimeFrom(time.Now().Add(time.Hour)).Ptr()}, false},
+		{"no question", PollInput{Options: []string{"Yes", "No"}}, true},
+		{"one option", PollInput{Question: "Lunch?", Options: []string{"Yes"}}, true},
+		{"too many options", PollInput{Question: "Lunch?", Options: tooManyOptions}, true},
+		{"empty option", PollInput{Question: "Lunch?", Options: []string{"Yes", ""}}, true},
+		{"closes in the past", PollInput{Question: "Lunch?", Options: []string{"Yes", "No"}, ClosesAt: null.TimeFrom(time.Now().Add(-time.Hour)).Ptr()}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.input.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Records the conversation writes of recordConversationMessage, a conversation is created by the first upsert
+// of its key and its event sequence increases on every following upsert like the UpsertConversation query.
+type conversationRecorder struct {
+	db.Querier
+	conversations map[string]db.Conversation
+	groupMembers  []db.InsertGroupConversationMembersParams
+	members       []db.UpsertConversationMemberParams
+	unreadCounts  []db.IncrementConversationUnreadCountsParams
+}
+
+func (r *conversationRecorder) UpsertConversation(ctx context.Context, arg db.UpsertConversationParams) (db.Conversation, error) {
+	c, ok := r.conversations[arg.ConversationKey]
+	if !ok {
+		c = db.Conversation{ID: int64(len(r.conversations) + 1), ConversationKey: arg.ConversationKey, GroupID: arg.GroupID}
+	}
+
+	c.LastMessageID = arg.LastMessageID
+	c.EventSequence++
+	r.conversations[arg.ConversationKey] = c
+
+	return c, nil
+}
+
+func (r *conversationRecorder) InsertGroupConversationMembers(ctx context.Context, arg db.InsertGroupConversationMembersParams) error {
+	r.groupMembers = append(r.groupMembers, arg)
+	return nil
+}
+
+func (r *conversationRecorder) UpsertConversationMember(ctx context.Context, arg db.UpsertConversationMemberParams) error {
+	if arg.UserID == 0 || arg.PeerUserID == nil || *arg.PeerUserID == 0 {
+		return fmt.Errorf("conversation member without a user: %+v", arg)
+	}
+
+	r.members = append(r.members, arg)
+	return nil
+}
+
+func (r *conversationRecorder) IncrementConversationUnreadCounts(ctx context.Context, arg db.IncrementConversationUnreadCountsParams) error {
+	r.unreadCounts = append(r.unreadCounts, arg)
+	return nil
+}
+
+func TestRecordConversationMessage(t *testing.T) {
+	groupMessage := db.Message{SenderID: 3, GroupID: null.IntFrom(7).Ptr()}
+	directMessage := db.Message{SenderID: 3, RecipientID: null.IntFrom(9).Ptr()}
+
+	tests := []struct {
+		name             string
+		messages         []db.Message
+		wantGroupMembers int
+		wantMembers      int
+	}{
+		{"one group message", []db.Message{groupMessage}, 1, 0},
+		{"two messages to the same group", []db.Message{groupMessage, groupMessage}, 1, 0},
+		{"two direct messages", []db.Message{directMessage, directMessage}, 0, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &conversationRecorder{conversations: make(map[string]db.Conversation)}
+
+			for idx, m := range tt.messages {
+				m.ID = int64(idx + 1)
+
+				c, err := recordConversationMessage(context.Background(), r, m)
+				if err != nil {
+					t.Fatalf("recordConversationMessage() error = %v", err)
+				}
+
+				if c.LastMessageID != m.ID {
+					t.Errorf("recordConversationMessage() last message = %d, want %d", c.LastMessageID, m.ID)
+				}
+			}
+
+			if len(r.conversations) != 1 {
+				t.Errorf("recorded %d conversations, want 1", len(r.conversations))
+			}
+
+			if len(r.groupMembers) != tt.wantGroupMembers {
+				t.Errorf("inserted group members %d times, want %d", len(r.groupMembers), tt.wantGroupMembers)
+			}
+
+			if len(r.members) != tt.wantMembers {
+				t.Errorf("upserted %d conversation members, want %d", len(r.members), tt.wantMembers)
+			}
+
+			if len(r.unreadCounts) != len(tt.messages) {
+				t.Errorf("incremented unread counts %d times, want %d", len(r.unreadCounts), len(tt.messages))
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"bytes"
 	"context"
+	"encoding/binary"
 	"fmt"
 	"image"
 	"image/jpeg"
@@ -13,6 +14,7 @@ import (
 	"path"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
@@ -32,8 +34,12 @@ type UploadFileInput struct {
 	FileHeader        *multipart.FileHeader `json:"fileHeader"`
 	GenerateThumbnail *bool                 `json:"generateThumbnail"`
 	ThumbnailPosition *float64              `json:"thumbnailPosition"`
+	VoiceNote         *bool                 `json:"voiceNote"`
 }
 
+// Number of amplitude buckets in an audio waveform.
+const audioWaveformBuckets = 64
+
 func (s *UploadService) UploadFile(ctx context.Context, input UploadFileInput) (*model.FileUploadResult, error) {
 	contentType := input.FileHeader.Header.Get("Content-Type")
 
@@ -46,7 +52,9 @@ func (s *UploadService) UploadFile(ctx context.Context, input UploadFileInput) (
 
 	isVideo := strings.HasPrefix(contentType, "video")
 	isAudio := strings.HasPrefix(contentType, "audio")
+	isVoiceNote := isAudio && input.VoiceNote != nil && *input.VoiceNote
 	var localFilePath string
+	var body io.Reader = input.File
 
 	// if the file is an audio or a video then save it locally for duration extraction and thumbnail generation.
 	if isAudio || isVideo {
@@ -68,6 +76,34 @@ func (s *UploadService) UploadFile(ctx context.Context, input UploadFileInput) (
 			return nil, fmt.Errorf("failed to seek file to start: %w", err)
 		}
 
+		// Normalize the loudness of voice notes and transcode them to opus, the transcoded file is uploaded instead of the original.
+		if isVoiceNote {
+			transcodedFilePath := path.Join(os.TempDir(), fmt.Sprintf("voice_note_%s.ogg", uuid.NewString()))
+			defer os.Remove(transcodedFilePath)
+
+			if err := TranscodeVoiceNote(localFilePath, transcodedFilePath); err != nil {
+				return nil, err
+			}
+
+			transcodedFile, err := os.Open(transcodedFilePath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open transcoded voice note: %w", err)
+			}
+			defer transcodedFile.Close()
+
+			transcodedFileInfo, err := transcodedFile.Stat()
+			if err != nil {
+				return nil, fmt.Errorf("failed to stat transcoded voice note: %w", err)
+			}
+
+			localFilePath = transcodedFilePath
+			body = transcodedFile
+
+			metadata.Filename = strings.TrimSuffix(metadata.Filename, path.Ext(metadata.Filename)) + ".ogg"
+			metadata.ContentType = "audio/ogg"
+			metadata.Size = transcodedFileInfo.Size()
+		}
+
 		duration, err := GetMediaDuration(localFilePath)
 		if err != nil {
 			return nil, err
@@ -75,6 +111,16 @@ func (s *UploadService) UploadFile(ctx context.Context, input UploadFileInput) (
 
 		metadata.Duration = &duration
 
+		// Generate waveform if file is an audio
+		if isAudio {
+			waveform, err := GenerateAudioWaveform(localFilePath, audioWaveformBuckets)
+			if err != nil {
+				return nil, err
+			}
+
+			metadata.Waveform = waveform
+		}
+
 		// Generate thumbnail if file is a video
 		if isVideo {
 			thumbnailPath := path.Join(os.TempDir(), fmt.Sprintf("thumbnail_%s.jpg", uuid.NewString()))
@@ -129,6 +175,36 @@ func (s *UploadService) UploadFile(ctx context.Context, input UploadFileInput) (
 		}
 	}
 
+	return s.putObject(ctx, metadata, body)
+}
+
+// Upload a file generated by the server such as a chat export.
+func (s *UploadService) UploadObject(ctx context.Context, body io.Reader, filename string, contentType string, size int64) (*model.FileUploadResult, error) {
+	metadata := model.ObjectMetadata{
+		ID:          uuid.New(),
+		Filename:    filename,
+		ContentType: contentType,
+		Size:        size,
+	}
+
+	return s.putObject(ctx, metadata, body)
+}
+
+// Get a presigned link to download an object.
+func (s *UploadService) PresignObjectURL(ctx context.Context, objectKey string, ttl time.Duration) (string, error) {
+	req, err := s3.NewPresignClient(s.S3Client).PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.S3Bucket),
+		Key:    aws.String(objectKey),
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", err
+	}
+
+	return req.URL, nil
+}
+
+// Upload an object to s3, the metadata of the object is encoded into the object key.
+func (s *UploadService) putObject(ctx context.Context, metadata model.ObjectMetadata, body io.Reader) (*model.FileUploadResult, error) {
 	objectKey, err := metadata.GenerateObjectKey()
 	if err != nil {
 		return nil, err
@@ -141,9 +217,9 @@ func (s *UploadService) UploadFile(ctx context.Context, input UploadFileInput) (
 	_, err = s.S3Client.PutObject(ctx, &s3.PutObjectInput{
 		Bucket:             aws.String(s.S3Bucket),
 		Key:                aws.String(objectKey),
-		Body:               input.File,
-		ContentDisposition: aws.String(fmt.Sprintf("inline; filename=%q", input.FileHeader.Filename)),
-		ContentType:        aws.String(contentType),
+		Body:               body,
+		ContentDisposition: aws.String(fmt.Sprintf("inline; filename=%q", metadata.Filename)),
+		ContentType:        aws.String(metadata.ContentType),
 	})
 	if err != nil {
 		return nil, err
@@ -172,6 +248,76 @@ func GetMediaDuration(path string) (float64, error) {
 	return duration, nil
 }
 
+// GenerateAudioWaveform generates a downsampled waveform of the audio using ffmpeg,
+// each bucket holds the peak amplitude of its samples scaled to 0-255.
+func GenerateAudioWaveform(audioPath string, buckets int) ([]byte, error) {
+	// Decode to mono 16 bit pcm at a low sample rate, enough to compute the peaks.
+	cmd := exec.Command("ffmpeg", "-v", "error", "-i", audioPath, "-vn", "-ac", "1", "-ar", "8000", "-f", "s16le", "-")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode audio for waveform: %w", err)
+	}
+
+	return waveformFromPCM(output, buckets)
+}
+
+// Downsample mono 16 bit little endian pcm into buckets holding the peak amplitude of their samples scaled to 0-255.
+func waveformFromPCM(pcm []byte, buckets int) ([]byte, error) {
+	samples := len(pcm) / 2
+	if samples == 0 {
+		return nil, fmt.Errorf("failed to generate waveform: audio has no samples")
+	}
+
+	peaks := make([]int, buckets)
+	maxPeak := 0
+
+	for i := 0; i < samples; i++ {
+		amplitude := int(int16(binary.LittleEndian.Uint16(pcm[i*2:])))
+		if amplitude < 0 {
+			amplitude = -amplitude
+		}
+
+		bucket := i * buckets / samples
+		if amplitude > peaks[bucket] {
+			peaks[bucket] = amplitude
+		}
+
+		if amplitude > maxPeak {
+			maxPeak = amplitude
+		}
+	}
+
+	waveform := make([]byte, buckets)
+
+	if maxPeak == 0 {
+		return waveform, nil
+	}
+
+	for idx, peak := range peaks {
+		waveform[idx] = byte(peak * 255 / maxPeak)
+	}
+
+	return waveform, nil
+}
+
+// TranscodeVoiceNote normalizes the loudness of the audio and transcodes it to opus using ffmpeg
+func TranscodeVoiceNote(audioPath, outputPath string) error {
+	cmd := exec.Command("ffmpeg", "-v", "error", "-i", audioPath,
+		"-vn",
+		"-af", "loudnorm=I=-16:TP=-1.5:LRA=11",
+		"-ac", "1",
+		"-c:a", "libopus",
+		"-b:a", "32k",
+		"-f", "ogg",
+		outputPath,
+	)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to transcode voice note: %w", err)
+	}
+
+	return nil
+}
+
 // GenerateVideoThumbnail generates a thumbnail for the video using ffmpeg
 func GenerateVideoThumbnail(videoPath, thumbnailPath string, position float64) (image.Image, error) {
 	cmd := exec.Command("ffmpeg", "-i", videoPath, "-ss", fmt.Sprint(position), "-vframes", "1", thumbnailPath)
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// Encode samples as mono 16 bit little endian pcm.
+func pcmSamples(samples ...int16) []byte {
+	b := make([]byte, len(samples)*2)
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(b[i*2:], uint16(s))
+	}
+
+	return b
+}
+
+func TestWaveformFromPCM(t *testing.T) {
+	tests := []struct {
+		name    string
+		pcm     []byte
+		buckets int
+		want    []byte
+		wantErr bool
+	}{
+		{"no samples", nil, 4, nil, true},
+		{"silence", pcmSamples(0, 0, 0, 0), 2, []byte{0, 0}, false},
+		{"one sample per bucket", pcmSamples(100, 50, 25, 0), 4, []byte{255, 127, 63, 0}, false},
+		{"peak of each bucket", pcmSamples(10, 100, 20, 50), 2, []byte{255, 127}, false},
+		{"negative amplitudes", pcmSamples(-100, 50, -32768, 0), 2, []byte{0, 255}, false},
+		{"fewer samples than buckets", pcmSamples(100, 50), 4, []byte{255, 0, 127, 0}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := waveformFromPCM(tt.pcm, tt.buckets)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("waveformFromPCM() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("waveformFromPCM() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
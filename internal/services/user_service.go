@@ -5,8 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"math"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	vd "github.com/go-ozzo/ozzo-validation/v4"
@@ -23,9 +25,25 @@ import (
 	"github.com/thanishsid/dingilink-server/internal/db"
 	"github.com/thanishsid/dingilink-server/internal/model"
 	"github.com/thanishsid/dingilink-server/internal/pkg/security"
+	"github.com/thanishsid/dingilink-server/internal/types"
 	"github.com/thanishsid/dingilink-server/internal/types/apperror"
 )
 
+const (
+	defaultUserSearchPageSize int64 = 20
+	maxUserSearchPageSize     int64 = 100
+	minUserSearchQueryLength        = 3
+
+	maxNearbyUsers            int64   = 100
+	nearbyLocationMaxAge              = time.Hour * 24
+	nearbyLocationGridDegrees float64 = 0.01
+	minLocationChangeInterval         = time.Minute * 10
+)
+
+// Radii nearby users can be searched within, only the distance buckets are allowed
+// so the distance to a user can't be narrowed down with small radii.
+var nearbyRadiiKm = []any{1.0, 5.0, 10.0, 50.0}
+
 type UserService struct {
 	DB                   db.DBQ
 	Mail                 *mailgo.Client
@@ -744,4 +762,294 @@ func (s *UserService) UpdateCurrentUser(ctx context.Context, input UpdateCurrent
 	}, nil
 }
 
+type SearchUsersInput struct {
+	Query string  `json:"query"`
+	First *int64  `json:"first"`
+	After *string `json:"after"`
+}
+
+func (i SearchUsersInput) Validate() error {
+	return vd.ValidateStruct(&i,
+		vd.Field(&i.Query,
+			vd.Required.Error(apperror.INPUT_REQUIRED),
+			vd.RuneLength(minUserSearchQueryLength, 0).Error(apperror.INPUT_TOO_LOW),
+			vd.RuneLength(0, 100).Error(apperror.INPUT_TOO_HIGH),
+		),
+		vd.Field(&i.First,
+			vd.Min(int64(1)).Error(apperror.INPUT_TOO_LOW),
+			vd.Max(maxUserSearchPageSize).Error(apperror.INPUT_TOO_HIGH),
+		),
+	)
+}
+
+// Search users by the similarity of their username or name to the query, friends and members of
+// the same groups are ranked higher. Deleted users and users blocked in either direction are excluded.
+func (s *UserService) SearchUsers(ctx context.Context, input SearchUsersInput) (*model.UserConnection, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	input.Query = strings.TrimSpace(input.Query)
+
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Results are ranked by relevance so the cursor is the position of the user in the results.
+	offset, err := parseCursor(input.After)
+	if err != nil || (offset != nil && *offset < 0) {
+		return nil, vd.Errors{"after": errors.New(apperror.INPUT_INVALID)}
+	}
+
+	params := db.SearchUserIDsParams{
+		Search:        input.Query,
+		PrefixPattern: escapeLikePattern(input.Query) + "%",
+		UserID:        userInfo.User.ID,
+		ResultLimit:   defaultUserSearchPageSize,
+	}
+
+	if input.First != nil {
+		params.ResultLimit = *input.First
+	}
+
+	if offset != nil {
+		params.ResultOffset = *offset
+	}
+
+	limit := params.ResultLimit
+
+	// One extra row is requested to find out if there are more users.
+	params.ResultLimit++
+
+	userIDs, err := s.DB.SearchUserIDs(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	hasNextPage := int64(len(userIDs)) > limit
+	if hasNextPage {
+		userIDs = userIDs[:limit]
+	}
+
+	users, err := s.DB.GetBatchedUsers(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	usersMap := make(map[int64]db.GetBatchedUsersRow, len(users))
+	for _, u := range users {
+		usersMap[u.ID] = u
+	}
+
+	edges := make([]model.UserEdge, 0, len(userIDs))
+
+	for idx, userID := range userIDs {
+		u, ok := usersMap[userID]
+		if !ok {
+			continue
+		}
+
+		edges = append(edges, model.UserEdge{
+			Node:   newPublicUser(u),
+			Cursor: strconv.FormatInt(params.ResultOffset+int64(idx)+1, 10),
+		})
+	}
+
+	connection := model.UserConnection{
+		Edges: edges,
+		PageInfo: model.PageInfo{
+			HasNextPage:     hasNextPage,
+			HasPreviousPage: params.ResultOffset > 0,
+		},
+	}
+
+	if len(edges) > 0 {
+		connection.PageInfo.StartCursor = &edges[0].Cursor
+		connection.PageInfo.EndCursor = &edges[len(edges)-1].Cursor
+	}
+
+	return &connection, nil
+}
+
+// Turn location sharing on or off, turning it on stores the current location of the user
+// and turning it off removes the stored location.
+func (s *UserService) SetLocationVisibility(ctx context.Context, visible bool) error {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return err
+	}
+
+	if !visible {
+		return s.DB.UpsertUserLocation(ctx, db.UpsertUserLocationParams{
+			UserID:  userInfo.User.ID,
+			Visible: false,
+		})
+	}
+
+	if !userInfo.Location.Valid {
+		return apperror.ErrLocationRequired
+	}
+
+	current, err := s.getUserLocation(ctx, userInfo.User.ID)
+	if err != nil {
+		return err
+	}
+
+	location, err := nextUserLocation(current, snapToLocationGrid(userInfo.Location), time.Now())
+	if err != nil {
+		return err
+	}
+
+	return s.DB.UpsertUserLocation(ctx, db.UpsertUserLocationParams{
+		UserID:   userInfo.User.ID,
+		Location: location,
+		Visible:  true,
+	})
+}
+
+// Get the users sharing their location within the radius of the current location of the user ordered by distance.
+// Only users sharing their own location can see nearby users, the location of the user is updated on each request
+// unless it was changed within the minimum interval.
+func (s *UserService) GetNearbyUsers(ctx context.Context, radiusKm float64) ([]*model.NearbyUser, error) {
+	userInfo, err := security.Authorize(ctx, security.User)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := vd.Validate(radiusKm,
+		vd.In(nearbyRadiiKm...).Error(apperror.INPUT_INVALID),
+	); err != nil {
+		return nil, vd.Errors{"radiusKm": err}
+	}
+
+	if !userInfo.Location.Valid {
+		return nil, apperror.ErrLocationRequired
+	}
+
+	current, err := s.getUserLocation(ctx, userInfo.User.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if current == nil || !current.Visible {
+		return nil, apperror.ErrLocationSharingDisabled
+	}
+
+	requested := snapToLocationGrid(userInfo.Location)
+
+	location, err := nextUserLocation(current, requested, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// A kept location isn't stored again so the rate limit window isn't extended by each request.
+	if location == requested {
+		if err := s.DB.UpsertUserLocation(ctx, db.UpsertUserLocationParams{
+			UserID:   userInfo.User.ID,
+			Location: location,
+			Visible:  true,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	nearby, err := s.DB.GetNearbyUsers(ctx, db.GetNearbyUsersParams{
+		Location:     location,
+		UserID:       userInfo.User.ID,
+		UpdatedAfter: pgtype.Timestamptz{Time: time.Now().Add(-nearbyLocationMaxAge), Valid: true},
+		RadiusMeters: radiusKm * 1000,
+		ResultLimit:  maxNearbyUsers,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]int64, len(nearby))
+	for i, n := range nearby {
+		userIDs[i] = n.UserID
+	}
+
+	users, err := s.DB.GetBatchedUsers(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	usersMap := make(map[int64]db.GetBatchedUsersRow, len(users))
+	for _, u := range users {
+		usersMap[u.ID] = u
+	}
+
+	result := make([]*model.NearbyUser, 0, len(nearby))
+
+	for _, n := range nearby {
+		u, ok := usersMap[n.UserID]
+		if !ok {
+			continue
+		}
+
+		result = append(result, &model.NearbyUser{
+			User:     newPublicUser(u),
+			Distance: nearbyDistance(n.Distance),
+		})
+	}
+
+	return result, nil
+}
+
+// Get the stored location of the user, nil when the user never shared their location.
+func (s *UserService) getUserLocation(ctx context.Context, userID int64) (*db.UserLocation, error) {
+	location, err := s.DB.GetUserLocation(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &location, nil
+}
+
+// Get the location to use for the user, a change of the location within the minimum interval
+// of the last update keeps the stored location so fake positions can't be swept quickly.
+func nextUserLocation(current *db.UserLocation, location types.Point, now time.Time) (types.Point, error) {
+	if current == nil || !current.UpdatedAt.Valid || now.Sub(current.UpdatedAt.Time) >= minLocationChangeInterval {
+		return location, nil
+	}
+
+	if !current.Location.Valid {
+		return types.Point{}, apperror.ErrLocationChangedTooOften
+	}
+
+	return current.Location, nil
+}
+
+// Round the coordinates of the location to the nearby location grid, about 1 km apart.
+func snapToLocationGrid(location types.Point) types.Point {
+	if !location.Valid {
+		return location
+	}
+
+	return types.NewCoordinates(
+		math.Round(location.Y/nearbyLocationGridDegrees)*nearbyLocationGridDegrees,
+		math.Round(location.X/nearbyLocationGridDegrees)*nearbyLocationGridDegrees,
+	)
+}
+
+// Get the coarse distance bucket of a distance in meters.
+func nearbyDistance(meters float64) model.NearbyDistance {
+	switch {
+	case meters <= 1000:
+		return model.NearbyDistanceWithin1Km
+	case meters <= 5000:
+		return model.NearbyDistanceWithin5Km
+	case meters <= 10000:
+		return model.NearbyDistanceWithin10Km
+	case meters <= 25000:
+		return model.NearbyDistanceWithin25Km
+	default:
+		return model.NearbyDistanceWithin50Km
+	}
+}
+
 //TODO - ADD CREATE USER FUNCTION FOR ADMINS
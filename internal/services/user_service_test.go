@@ -0,0 +1,171 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/jackc/pgx/v5/pgtype"
+	"gopkg.in/guregu/null.v4"
+
+	"github.com/thanishsid/dingilink-server/internal/db"
+	"github.com/thanishsid/dingilink-server/internal/model"
+	"github.com/thanishsid/dingilink-server/internal/types"
+	"github.com/thanishsid/dingilink-server/internal/types/apperror"
+)
+
+func TestSearchUsersInputValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   SearchUsersInput
+		wantErr bool
+	}{
+		{"valid", SearchUsersInput{Query: "jan"}, false},
+		{"with first", SearchUsersInput{Query: "jane", First: null.IntFrom(10).Ptr()}, false},
+		{"empty", SearchUsersInput{}, true},
+		{"too short", SearchUsersInput{Query: "ja"}, true},
+		{"wildcard only", SearchUsersInput{Query: "%"}, true},
+		{"too long", SearchUsersInput{Query: string(make([]rune, 101))}, true},
+		{"first too high", SearchUsersInput{Query: "jane", First: null.IntFrom(maxUserSearchPageSize + 1).Ptr()}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.input.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEscapeLikePattern(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"jane", "jane"},
+		{"%%%", `\%\%\%`},
+		{"___", `\_\_\_`},
+		{`\`, `\\`},
+		{`j%a_n\e`, `j\%a\_n\\e`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := escapeLikePattern(tt.value); got != tt.want {
+				t.Errorf("escapeLikePattern() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNearbyDistance(t *testing.T) {
+	tests := []struct {
+		meters float64
+		want   model.NearbyDistance
+	}{
+		{0, model.NearbyDistanceWithin1Km},
+		{1000, model.NearbyDistanceWithin1Km},
+		{1000.5, model.NearbyDistanceWithin5Km},
+		{5000, model.NearbyDistanceWithin5Km},
+		{9999, model.NearbyDistanceWithin10Km},
+		{25000, model.NearbyDistanceWithin25Km},
+		{25001, model.NearbyDistanceWithin50Km},
+		{50000, model.NearbyDistanceWithin50Km},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.meters), func(t *testing.T) {
+			if got := nearbyDistance(tt.meters); got != tt.want {
+				t.Errorf("nearbyDistance() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnapToLocationGrid(t *testing.T) {
+	tests := []struct {
+		name     string
+		location types.Point
+		want     types.Point
+	}{
+		{"rounds down", types.NewCoordinates(6.92712, 79.86123), types.NewCoordinates(6.93, 79.86)},
+		{"rounds up", types.NewCoordinates(6.92512, 79.85501), types.NewCoordinates(6.93, 79.86)},
+		{"negative", types.NewCoordinates(-33.86882, 151.20929), types.NewCoordinates(-33.87, 151.21)},
+		{"invalid", types.Point{}, types.Point{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := snapToLocationGrid(tt.location)
+			if got.Valid != tt.want.Valid || math.Abs(got.Y-tt.want.Y) > 1e-9 || math.Abs(got.X-tt.want.X) > 1e-9 {
+				t.Errorf("snapToLocationGrid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextUserLocation(t *testing.T) {
+	now := time.Now()
+	stored := types.NewCoordinates(6.93, 79.86)
+	moved := types.NewCoordinates(6.95, 79.86)
+
+	updatedAt := func(d time.Duration) pgtype.Timestamptz {
+		return pgtype.Timestamptz{Time: now.Add(-d), Valid: true}
+	}
+
+	tests := []struct {
+		name    string
+		current *db.UserLocation
+		want    types.Point
+		wantErr error
+	}{
+		{"never shared", nil, moved, nil},
+		{"changed after the interval", &db.UserLocation{Location: stored, UpdatedAt: updatedAt(minLocationChangeInterval)}, moved, nil},
+		{"changed within the interval", &db.UserLocation{Location: stored, UpdatedAt: updatedAt(time.Minute)}, stored, nil},
+		{"turned off within the interval", &db.UserLocation{UpdatedAt: updatedAt(time.Minute)}, types.Point{}, apperror.ErrLocationChangedTooOften},
+		{"turned off after the interval", &db.UserLocation{UpdatedAt: updatedAt(minLocationChangeInterval)}, moved, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := nextUserLocation(tt.current, moved, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("nextUserLocation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("nextUserLocation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetNearbyUsersRadius(t *testing.T) {
+	tests := []struct {
+		radiusKm float64
+		wantErr  bool
+	}{
+		{1, false},
+		{5, false},
+		{10, false},
+		{50, false},
+		{0.1, true},
+		{2.5, true},
+		{25, true},
+		{100, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.radiusKm), func(t *testing.T) {
+			err := vd.Validate(tt.radiusKm, vd.In(nearbyRadiiKm...))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -23,6 +23,19 @@ var (
 	ErrInvalidEmailVerificationToken = NewError("INVALID_EMAIL_VERIFICATION_TOKEN", "your email verification link is no longer valid", http.StatusBadRequest)
 	ErrEmailAlreadyVerified          = NewError("EMAIL_ALREADY_VERIFIED", "your email has already been verified", http.StatusBadRequest)
 	ErrAccountNotFound               = NewError("ACCOUNT_NOT_FOUND", "unable to find your account, please make sure you have registered", http.StatusNotFound)
+	ErrPollClosed                    = NewError("POLL_CLOSED", "the poll is closed and no longer accepts votes", http.StatusBadRequest)
+	ErrUserBlocked                   = NewError("USER_BLOCKED", "you can not message this user", http.StatusForbidden)
+	ErrUserUnavailable               = NewError("USER_UNAVAILABLE", "this user is no longer available", http.StatusBadRequest)
+	ErrGroupOwnerCannotLeave         = NewError("GROUP_OWNER_CANNOT_LEAVE", "transfer the ownership of the group before leaving", http.StatusBadRequest)
+	ErrGroupAdminsOnly               = NewError("GROUP_ADMINS_ONLY", "only the admins of the group can perform this action", http.StatusForbidden)
+	ErrInvalidGroupInvite            = NewError("INVALID_GROUP_INVITE", "this invite link is invalid or has expired", http.StatusBadRequest)
+	ErrChannelOwnerCannotLeave       = NewError("CHANNEL_OWNER_CANNOT_LEAVE", "the owner of a channel can not leave the channel", http.StatusBadRequest)
+	ErrChannelAdminsOnly             = NewError("CHANNEL_ADMINS_ONLY", "only the admins of the channel can perform this action", http.StatusForbidden)
+	ErrInvalidChannelInvite          = NewError("INVALID_CHANNEL_INVITE", "this invite link is invalid or has been reset", http.StatusBadRequest)
+	ErrCannotBefriendUser            = NewError("CANNOT_BEFRIEND_USER", "you can not send a friend request to this user", http.StatusForbidden)
+	ErrLocationRequired              = NewError("LOCATION_REQUIRED", "your location is required, make sure location access is enabled", http.StatusBadRequest)
+	ErrLocationSharingDisabled       = NewError("LOCATION_SHARING_DISABLED", "turn on location sharing to see people nearby", http.StatusForbidden)
+	ErrLocationChangedTooOften       = NewError("LOCATION_CHANGED_TOO_OFTEN", "your location was changed too recently, please try again later", http.StatusTooManyRequests)
 )
 
 func NewError(code string, msg string, httpCode ...int) *Error {